@@ -6,9 +6,15 @@
 package helper
 
 import (
+	"context"
 	"os/exec"
 )
 
 func NewCommand(name string, arg ...string) *exec.Cmd {
 	return exec.Command(name, arg...)
 }
+
+// NewShellCommand runs a user-written command line through sh.
+func NewShellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -6,6 +6,7 @@
 package helper
 
 import (
+	"context"
 	"os/exec"
 	"syscall"
 )
@@ -21,3 +22,14 @@ func NewCommand(name string, arg ...string) *exec.Cmd {
 
 	return cmd
 }
+
+// NewShellCommand runs a user-written command line through cmd.exe. The
+// command line is passed as is, so quoted paths reach cmd.exe unescaped.
+func NewShellCommand(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+		CmdLine:    `cmd.exe /S /C "` + command + `"`,
+	}
+	return cmd
+}
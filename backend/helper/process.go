@@ -0,0 +1,100 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Process is a running process as seen by a ProcessController.
+type Process struct {
+	PID int
+	// Name is the executable's image name, e.g. "EpicGamesLauncher.exe".
+	Name string
+}
+
+// ProcessController lists, stops and starts processes. Image names are
+// matched case-insensitively, as Windows does.
+type ProcessController interface {
+	// List returns every running process.
+	List() ([]Process, error)
+	// FindByName returns the running processes with the given image name.
+	FindByName(imageName string) ([]Process, error)
+	// Terminate force-kills p. A process that already exited is not an error.
+	Terminate(p Process) error
+	// WaitForExit polls until no process with the image name is running,
+	// and reports false if one still is when maxWait elapses or ctx is done.
+	WaitForExit(ctx context.Context, imageName string, maxWait time.Duration) bool
+	// Start launches the executable at path without waiting for it.
+	Start(path string, args ...string) error
+}
+
+// processPollInterval is how often WaitForExit checks the process list.
+const processPollInterval = 250 * time.Millisecond
+
+// IsRunning reports whether a process with the given image name is running.
+// A failure to list processes counts as not running.
+func IsRunning(pc ProcessController, imageName string) bool {
+	procs, err := pc.FindByName(imageName)
+	return err == nil && len(procs) > 0
+}
+
+// TerminateAll force-kills every process with the given image name and
+// returns how many were found.
+func TerminateAll(pc ProcessController, imageName string) (int, error) {
+	procs, err := pc.FindByName(imageName)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range procs {
+		if err := pc.Terminate(p); err != nil {
+			return len(procs), err
+		}
+	}
+	return len(procs), nil
+}
+
+// findByName filters list down to processes with the given image name.
+func findByName(list func() ([]Process, error), imageName string) ([]Process, error) {
+	procs, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	found := []Process{}
+	for _, p := range procs {
+		if strings.EqualFold(p.Name, imageName) {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+// waitForExit implements WaitForExit on top of FindByName.
+func waitForExit(ctx context.Context, pc ProcessController, imageName string, maxWait time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	ticker := time.NewTicker(processPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !IsRunning(pc, imageName) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// imageName returns the file name of an executable path, accepting both
+// Windows and Unix separators so Wine paths work anywhere.
+func imageName(path string) string {
+	if i := strings.LastIndexAny(path, `\/`); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
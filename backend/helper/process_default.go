@@ -0,0 +1,111 @@
+//go:build !windows
+
+// This file is used for non-windows platforms
+// using Go build tag (marking per-OS source files)
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// wineProcesses reads /proc, so Windows programs running under Wine are
+// found by their .exe image name the same way native ones are.
+type wineProcesses struct {
+	procDir string
+}
+
+// NewProcessController returns the native controller for this platform.
+func NewProcessController() ProcessController {
+	return &wineProcesses{procDir: "/proc"}
+}
+
+func (w *wineProcesses) List() ([]Process, error) {
+	entries, err := os.ReadDir(w.procDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	procs := []Process{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if name := w.processName(pid); name != "" {
+			procs = append(procs, Process{PID: pid, Name: name})
+		}
+	}
+	return procs, nil
+}
+
+// processName prefers argv[0], since Wine puts the Windows path of the
+// .exe there while comm is cut to 15 characters.
+func (w *wineProcesses) processName(pid int) string {
+	dir := filepath.Join(w.procDir, strconv.Itoa(pid))
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		if argv0, _, _ := strings.Cut(string(cmdline), "\x00"); argv0 != "" {
+			return imageName(argv0)
+		}
+	}
+	comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return "" // Exited while listing
+	}
+	return strings.TrimSpace(string(comm))
+}
+
+func (w *wineProcesses) FindByName(imageName string) ([]Process, error) {
+	return findByName(w.List, imageName)
+}
+
+func (w *wineProcesses) Terminate(p Process) error {
+	if err := syscall.Kill(p.PID, syscall.SIGKILL); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil // Already gone
+		}
+		return fmt.Errorf("failed to terminate %s (%d): %w", p.Name, p.PID, err)
+	}
+	return nil
+}
+
+func (w *wineProcesses) WaitForExit(ctx context.Context, imageName string, maxWait time.Duration) bool {
+	return waitForExit(ctx, w, imageName, maxWait)
+}
+
+// Start runs .exe files through Wine ($WINE, or "wine" on the PATH) and
+// anything else directly.
+func (w *wineProcesses) Start(path string, args ...string) error {
+	name := path
+	if strings.EqualFold(filepath.Ext(imageName(path)), ".exe") {
+		wine := os.Getenv("WINE")
+		if wine == "" {
+			wine = "wine"
+		}
+		if _, err := exec.LookPath(wine); err != nil {
+			return fmt.Errorf("wine is required to start %s: %w", imageName(path), err)
+		}
+		name = wine
+		args = append([]string{path}, args...)
+	}
+
+	cmd := NewCommand(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Reap it when it exits, so it doesn't linger as a zombie
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,113 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeProcessController is an in-memory ProcessController, so the switch
+// flow and its failure paths can be exercised on any OS without touching
+// real processes. Set the error fields to simulate failures.
+type FakeProcessController struct {
+	mu      sync.Mutex
+	nextPID int
+	procs   []Process
+	started [][]string
+
+	// ListErr is returned by List and FindByName.
+	ListErr error
+	// TerminateErr is returned by Terminate, which then leaves the process running.
+	TerminateErr error
+	// StartErr is returned by Start, which then starts nothing.
+	StartErr error
+	// Unkillable image names survive Terminate, so WaitForExit times out.
+	Unkillable map[string]bool
+}
+
+// NewFakeProcessController returns a fake with one running process per
+// image name given.
+func NewFakeProcessController(imageNames ...string) *FakeProcessController {
+	f := &FakeProcessController{nextPID: 1000, Unkillable: map[string]bool{}}
+	for _, name := range imageNames {
+		f.Spawn(name)
+	}
+	return f
+}
+
+// Spawn adds a running process with the given image name.
+func (f *FakeProcessController) Spawn(imageName string) Process {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextPID++
+	p := Process{PID: f.nextPID, Name: imageName}
+	f.procs = append(f.procs, p)
+	return p
+}
+
+// Started returns the command lines passed to Start, in order.
+func (f *FakeProcessController) Started() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	started := make([][]string, len(f.started))
+	copy(started, f.started)
+	return started
+}
+
+func (f *FakeProcessController) List() ([]Process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.ListErr != nil {
+		return nil, f.ListErr
+	}
+	return append([]Process{}, f.procs...), nil
+}
+
+func (f *FakeProcessController) FindByName(imageName string) ([]Process, error) {
+	return findByName(f.List, imageName)
+}
+
+func (f *FakeProcessController) Terminate(p Process) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.TerminateErr != nil {
+		return f.TerminateErr
+	}
+	for name := range f.Unkillable {
+		if strings.EqualFold(name, p.Name) {
+			return nil
+		}
+	}
+
+	kept := []Process{}
+	for _, running := range f.procs {
+		if running.PID != p.PID {
+			kept = append(kept, running)
+		}
+	}
+	f.procs = kept
+	return nil
+}
+
+func (f *FakeProcessController) WaitForExit(ctx context.Context, imageName string, maxWait time.Duration) bool {
+	return waitForExit(ctx, f, imageName, maxWait)
+}
+
+// Start records the command line and adds a running process named after path.
+func (f *FakeProcessController) Start(path string, args ...string) error {
+	f.mu.Lock()
+	if f.StartErr != nil {
+		f.mu.Unlock()
+		return f.StartErr
+	}
+	f.started = append(f.started, append([]string{path}, args...))
+	f.mu.Unlock()
+
+	f.Spawn(imageName(path))
+	return nil
+}
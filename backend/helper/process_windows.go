@@ -0,0 +1,96 @@
+//go:build windows
+
+// This file is used for windows platforms
+// using Go build tag (marking per-OS source files)
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// terminateWait is how long Terminate waits for a killed process to be gone,
+// matching what taskkill /F used to block for.
+const terminateWait = 5 * time.Second
+
+// windowsProcesses talks to the Win32 process APIs directly instead of
+// parsing tasklist/taskkill output, which is localized.
+type windowsProcesses struct{}
+
+// NewProcessController returns the native controller for this platform.
+func NewProcessController() ProcessController {
+	return windowsProcesses{}
+}
+
+func (windowsProcesses) List() ([]Process, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot processes: %w", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	procs := []Process{}
+	err = windows.Process32First(snapshot, &entry)
+	for err == nil {
+		procs = append(procs, Process{
+			PID:  int(entry.ProcessID),
+			Name: windows.UTF16ToString(entry.ExeFile[:]),
+		})
+		err = windows.Process32Next(snapshot, &entry)
+	}
+	if !errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return procs, nil
+}
+
+func (w windowsProcesses) FindByName(imageName string) ([]Process, error) {
+	return findByName(w.List, imageName)
+}
+
+func (windowsProcesses) Terminate(p Process) error {
+	handle, err := windows.OpenProcess(windows.PROCESS_TERMINATE|windows.SYNCHRONIZE, false, uint32(p.PID))
+	if err != nil {
+		if errors.Is(err, windows.ERROR_INVALID_PARAMETER) {
+			return nil // Already gone
+		}
+		return fmt.Errorf("failed to open %s (%d): %w", p.Name, p.PID, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	if err := windows.TerminateProcess(handle, 1); err != nil {
+		// Access is denied for a process that is already exiting
+		if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+			return nil
+		}
+		return fmt.Errorf("failed to terminate %s (%d): %w", p.Name, p.PID, err)
+	}
+
+	// Wait until the OS has released its handles, like taskkill /F does
+	windows.WaitForSingleObject(handle, uint32(terminateWait.Milliseconds()))
+	return nil
+}
+
+func (w windowsProcesses) WaitForExit(ctx context.Context, imageName string, maxWait time.Duration) bool {
+	return waitForExit(ctx, w, imageName, maxWait)
+}
+
+func (windowsProcesses) Start(path string, args ...string) error {
+	cmd := NewCommand(path, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
@@ -11,8 +11,9 @@ import (
 )
 
 // AvatarHandler creates a middleware that intercepts requests for custom avatars
-// and serves them from the local app data directory.
-func AvatarHandler(avatarDir string) assetserver.Middleware {
+// and serves them from the local app data directory. avatarDir is called per
+// request, so avatars follow the active vault.
+func AvatarHandler(avatarDir func() string) assetserver.Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -38,7 +39,7 @@ func AvatarHandler(avatarDir string) assetserver.Middleware {
 				ext := filepath.Ext(filename)
 				base := strings.TrimSuffix(filename, ext)
 				thumbFilename := base + "_thumb" + ext
-				thumbPath := filepath.Join(avatarDir, thumbFilename)
+				thumbPath := filepath.Join(avatarDir(), thumbFilename)
 
 				if _, err := os.Stat(thumbPath); err == nil {
 					http.ServeFile(w, r, thumbPath)
@@ -52,7 +53,7 @@ func AvatarHandler(avatarDir string) assetserver.Middleware {
 			const prefix = "/avatar-full/"
 			if strings.HasPrefix(r.URL.Path, prefix) {
 				filename := strings.TrimPrefix(r.URL.Path, prefix)
-				avatarPath := filepath.Join(avatarDir, filename)
+				avatarPath := filepath.Join(avatarDir(), filename)
 
 				if _, err := os.Stat(avatarPath); err == nil {
 					http.ServeFile(w, r, avatarPath)
@@ -0,0 +1,36 @@
+package models
+
+// AppSettings holds user preferences persisted by the backend, in
+// app_settings.json. Frontend-only preferences stay in localStorage.
+type AppSettings struct {
+	// TrashRetentionDays is how long deleted accounts stay restorable.
+	// 0 keeps them until purged by hand.
+	TrashRetentionDays int `json:"trashRetentionDays"`
+
+	// VerifySwitch makes a switch wait to confirm the launcher actually
+	// signed in as the chosen account.
+	VerifySwitch bool `json:"verifySwitch"`
+	// VerifySwitchTimeoutSeconds is how long that check waits before giving
+	// up with an unknown result.
+	VerifySwitchTimeoutSeconds int `json:"verifySwitchTimeoutSeconds"`
+
+	// PreSwitchHook and PostSwitchHook are command lines run before and
+	// after a switch or a move-aside of the active session. Empty disables
+	// them.
+	PreSwitchHook  string `json:"preSwitchHook"`
+	PostSwitchHook string `json:"postSwitchHook"`
+	// HookTimeoutSeconds is how long a hook may run before it is killed.
+	HookTimeoutSeconds int `json:"hookTimeoutSeconds"`
+	// AbortOnPreHookFailure stops the switch when the pre-switch hook fails
+	// or times out. Otherwise the failure is only reported.
+	AbortOnPreHookFailure bool `json:"abortOnPreHookFailure"`
+}
+
+// DefaultAppSettings returns the settings used when none are saved yet.
+func DefaultAppSettings() AppSettings {
+	return AppSettings{
+		TrashRetentionDays:         30,
+		VerifySwitchTimeoutSeconds: 45,
+		HookTimeoutSeconds:         30,
+	}
+}
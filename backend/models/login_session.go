@@ -1,12 +1,19 @@
 package models
 
 type LoginSession struct {
-	Username    string `json:"username"`
-	UserID      string `json:"userId"`
-	Alias       string `json:"alias"`
-	LoginToken  string `json:"loginToken"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
-	AvatarImage string `json:"avatarImage"`
-	AvatarColor string `json:"avatarColor"`
+	Username         string   `json:"username"`
+	UserID           string   `json:"userId"`
+	Alias            string   `json:"alias"`
+	LoginToken       string   `json:"loginToken"`
+	TokenFingerprint string   `json:"tokenFingerprint,omitempty"`
+	CreatedAt        string   `json:"created_at"`
+	UpdatedAt        string   `json:"updated_at"`
+	AvatarImage      string   `json:"avatarImage"`
+	AvatarColor      string   `json:"avatarColor"`
+	Tags             []string `json:"tags,omitempty"`
+	SortOrder        int      `json:"sortOrder"`
+	Pinned           bool     `json:"pinned,omitempty"`
+	LastUsedAt       string   `json:"lastUsedAt,omitempty"`
+	SwitchCount      int      `json:"switchCount,omitempty"`
+	NeedsRelogin     bool     `json:"needsRelogin,omitempty"`
 }
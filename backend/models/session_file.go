@@ -0,0 +1,17 @@
+package models
+
+// SessionFile is the on-disk layout of login_sessions.json. Older releases
+// stored a bare array of sessions (schema version 0); those files are
+// upgraded to this envelope on load.
+type SessionFile struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	AppVersion    string           `json:"appVersion"`
+	Sessions      []LoginSession   `json:"sessions"`
+	Trash         []DeletedSession `json:"trash,omitempty"`
+}
+
+// DeletedSession is a session in the recycle bin, restorable until purged.
+type DeletedSession struct {
+	LoginSession
+	DeletedAt string `json:"deletedAt"`
+}
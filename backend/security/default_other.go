@@ -0,0 +1,206 @@
+//go:build !windows
+
+package security
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+// PassphraseEnvVar lets users supply their own passphrase for token
+// encryption on platforms without DPAPI.
+const PassphraseEnvVar = "EPIC_SWITCHER_PASSPHRASE"
+
+// keyFileName holds the salt used to derive the token encryption key. The
+// generated passphrase is kept in the system keyring, and only written here
+// as well when there is none.
+const keyFileName = "token.key"
+
+// errNoKeyring is returned by openKeyring when no keyring can be reached.
+var errNoKeyring = errors.New("no system keyring available")
+
+// keyring stores small secrets in the system keyring, found by attributes.
+type keyring interface {
+	get(attributes map[string]string) ([]byte, bool, error)
+	set(label string, attributes map[string]string, value []byte) error
+	close()
+}
+
+type keyFile struct {
+	Salt       []byte `json:"salt"`
+	Passphrase string `json:"passphrase,omitempty"`
+	// InKeyring records that the passphrase lives in the system keyring,
+	// so a missing keyring is an error rather than a reason to make a new
+	// key that can't decrypt the stored tokens.
+	InKeyring bool `json:"inKeyring,omitempty"`
+}
+
+// DefaultProtector returns the platform protector: AES-GCM under a key
+// derived from PassphraseEnvVar if set, otherwise from a random passphrase
+// generated once and kept in the system keyring (the Secret Service on
+// Linux).
+//
+// Without a keyring the passphrase is stored in keyDir with owner-only
+// permissions. That only protects the tokens as well as the folder's
+// permissions do: anyone who can copy the folder can decrypt them.
+func DefaultProtector(keyDir string) (Protector, error) {
+	path := filepath.Join(keyDir, keyFileName)
+	kf, err := readKeyFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token key: %w", err)
+	}
+
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		if kf == nil {
+			if kf, err = newKeyFile(nil); err != nil {
+				return nil, err
+			}
+			kf.Passphrase = ""
+			if err := writeKeyFile(path, kf); err != nil {
+				return nil, fmt.Errorf("failed to save token key: %w", err)
+			}
+		}
+		return NewPassphraseProtector("aes-gcm-env", passphrase, kf.Salt)
+	}
+
+	if kf, err = loadGeneratedKey(path, keyDir, kf, openKeyring); err != nil {
+		return nil, fmt.Errorf("failed to load token key: %w", err)
+	}
+	return NewPassphraseProtector("aes-gcm", kf.Passphrase, kf.Salt)
+}
+
+// loadGeneratedKey returns the generated passphrase and salt, reading them
+// from the keyring, moving a passphrase found in the key file there, or
+// creating them. kf is the key file at path, nil if there is none.
+func loadGeneratedKey(path, keyDir string, kf *keyFile, openKeyring func() (keyring, error)) (*keyFile, error) {
+	ring, err := openKeyring()
+	if err != nil {
+		if kf != nil && kf.InKeyring {
+			return nil, fmt.Errorf("the token key is kept in the system keyring: %w", err)
+		}
+		return fileKey(path, kf)
+	}
+	defer ring.close()
+
+	attributes := map[string]string{"application": utils.AppFolderName, "key-dir": keyDir}
+	stored, found, err := ring.get(attributes)
+	if err != nil {
+		if kf != nil && kf.InKeyring {
+			return nil, err
+		}
+		fmt.Printf("⚠️ Failed to read the token key from the keyring, using %s: %v\n", path, err)
+		return fileKey(path, kf)
+	}
+	if found {
+		var key keyFile
+		if err := json.Unmarshal(stored, &key); err != nil || key.Passphrase == "" {
+			return nil, fmt.Errorf("token key in the keyring is damaged")
+		}
+		if kf == nil || !kf.InKeyring || kf.Passphrase != "" {
+			// Drop a passphrase left in the file by an earlier move
+			if err := writeKeyFile(path, &keyFile{Salt: key.Salt, InKeyring: true}); err != nil {
+				return nil, fmt.Errorf("failed to save token key: %w", err)
+			}
+		}
+		return &key, nil
+	}
+	if kf != nil && kf.InKeyring {
+		return nil, fmt.Errorf("the token key is missing from the system keyring")
+	}
+
+	// Not in the keyring yet: move the file's passphrase there, so existing
+	// tokens still decrypt, or generate one
+	if kf == nil || kf.Passphrase == "" {
+		var salt []byte
+		if kf != nil {
+			salt = kf.Salt
+		}
+		if kf, err = newKeyFile(salt); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(keyFile{Salt: kf.Salt, Passphrase: kf.Passphrase})
+	if err != nil {
+		return nil, err
+	}
+	if err := ring.set(utils.AppFolderName+" token key", attributes, data); err != nil {
+		fmt.Printf("⚠️ Failed to store the token key in the keyring, using %s: %v\n", path, err)
+		return fileKey(path, kf)
+	}
+	if err := writeKeyFile(path, &keyFile{Salt: kf.Salt, InKeyring: true}); err != nil {
+		return nil, fmt.Errorf("failed to save token key: %w", err)
+	}
+	fmt.Println("🔑 Token key stored in the system keyring")
+	return kf, nil
+}
+
+// fileKey returns the passphrase kept in the key file, generating it if
+// needed, for when there is no keyring to keep it in.
+func fileKey(path string, kf *keyFile) (*keyFile, error) {
+	if kf != nil && kf.Passphrase != "" {
+		return kf, nil
+	}
+
+	var salt []byte
+	if kf != nil {
+		salt = kf.Salt
+	}
+	kf, err := newKeyFile(salt)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeKeyFile(path, kf); err != nil {
+		return nil, fmt.Errorf("failed to save token key: %w", err)
+	}
+	return kf, nil
+}
+
+// readKeyFile reads the key file at path, or returns nil if there is none.
+func readKeyFile(path string) (*keyFile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, err
+	}
+	return &kf, nil
+}
+
+// newKeyFile generates a random passphrase, and a salt unless one is given.
+func newKeyFile(salt []byte) (*keyFile, error) {
+	if salt == nil {
+		var err error
+		if salt, err = NewSalt(); err != nil {
+			return nil, err
+		}
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &keyFile{Salt: salt, Passphrase: base64.StdEncoding.EncodeToString(secret)}, nil
+}
+
+func writeKeyFile(path string, kf *keyFile) error {
+	data, err := json.Marshal(kf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(path, data, 0600)
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package security
+
+// DefaultProtector returns the platform protector: DPAPI on Windows.
+// keyDir is unused here; DPAPI manages its own keys.
+func DefaultProtector(keyDir string) (Protector, error) {
+	return DPAPIProtector{}, nil
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package security
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// DPAPIProtector encrypts values with the Windows Data Protection API, bound
+// to the current Windows user. Only that user on this machine can decrypt.
+type DPAPIProtector struct{}
+
+func (DPAPIProtector) Name() string { return "dpapi" }
+
+func (DPAPIProtector) Protect(plaintext []byte) ([]byte, error) {
+	var out windows.DataBlob
+	in := newDataBlob(plaintext)
+	if err := windows.CryptProtectData(in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeDataBlob(&out), nil
+}
+
+func (DPAPIProtector) Unprotect(ciphertext []byte) ([]byte, error) {
+	var out windows.DataBlob
+	in := newDataBlob(ciphertext)
+	if err := windows.CryptUnprotectData(in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeDataBlob(&out), nil
+}
+
+func newDataBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+// takeDataBlob copies a DPAPI-allocated blob into Go memory and frees it.
+func takeDataBlob(blob *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data)))
+	return append([]byte(nil), unsafe.Slice(blob.Data, blob.Size)...)
+}
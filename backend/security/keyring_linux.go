@@ -0,0 +1,177 @@
+//go:build linux
+
+package security
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Secret Service names, see https://specifications.freedesktop.org/secret-service/
+const (
+	secretsBusName      = "org.freedesktop.secrets"
+	secretsPath         = dbus.ObjectPath("/org/freedesktop/secrets")
+	secretsDefault      = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	secretsService      = "org.freedesktop.Secret.Service"
+	secretsCollection   = "org.freedesktop.Secret.Collection"
+	secretsItem         = "org.freedesktop.Secret.Item"
+	secretsPrompt       = "org.freedesktop.Secret.Prompt"
+	secretsNoPrompt     = dbus.ObjectPath("/")
+	secretPromptTimeout = 2 * time.Minute
+)
+
+// secretValue is the Secret struct the Secret Service API passes around.
+type secretValue struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretServiceKeyring stores secrets through the freedesktop Secret Service
+// (GNOME Keyring, KWallet and others) on the session bus.
+type secretServiceKeyring struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+func openKeyring() (keyring, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNoKeyring, err)
+	}
+
+	// The "plain" algorithm only leaves the secret unencrypted on the
+	// session bus, which is private to this user.
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = conn.Object(secretsBusName, secretsPath).
+		Call(secretsService+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", errNoKeyring, err)
+	}
+	return &secretServiceKeyring{conn: conn, session: session}, nil
+}
+
+func (k *secretServiceKeyring) get(attributes map[string]string) ([]byte, bool, error) {
+	var unlocked, locked []dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsPath).
+		Call(secretsService+".SearchItems", 0, attributes).
+		Store(&unlocked, &locked)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to search the keyring: %w", err)
+	}
+	if len(unlocked) == 0 && len(locked) > 0 {
+		if unlocked, err = k.unlock(locked); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(unlocked) == 0 {
+		return nil, false, nil
+	}
+
+	var secret secretValue
+	err = k.conn.Object(secretsBusName, unlocked[0]).
+		Call(secretsItem+".GetSecret", 0, k.session).
+		Store(&secret)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read from the keyring: %w", err)
+	}
+	return secret.Value, true, nil
+}
+
+func (k *secretServiceKeyring) set(label string, attributes map[string]string, value []byte) error {
+	if _, err := k.unlock([]dbus.ObjectPath{secretsDefault}); err != nil {
+		return err
+	}
+
+	properties := map[string]dbus.Variant{
+		secretsItem + ".Label":      dbus.MakeVariant(label),
+		secretsItem + ".Attributes": dbus.MakeVariant(attributes),
+	}
+	secret := secretValue{Session: k.session, Value: value, ContentType: "text/plain"}
+
+	var item, prompt dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsDefault).
+		Call(secretsCollection+".CreateItem", 0, properties, secret, true).
+		Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("failed to write to the keyring: %w", err)
+	}
+	if prompt != secretsNoPrompt {
+		_, err = k.prompt(prompt)
+	}
+	return err
+}
+
+// unlock unlocks objects, letting the keyring ask the user for its password
+// if it needs to, and returns the ones that are now unlocked.
+func (k *secretServiceKeyring) unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, error) {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsPath).
+		Call(secretsService+".Unlock", 0, objects).
+		Store(&unlocked, &prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock the keyring: %w", err)
+	}
+	if prompt == secretsNoPrompt {
+		return unlocked, nil
+	}
+
+	result, err := k.prompt(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Store(&unlocked); err != nil {
+		return nil, fmt.Errorf("unexpected keyring unlock result: %w", err)
+	}
+	return unlocked, nil
+}
+
+// prompt shows a keyring prompt and waits for the user to complete it.
+func (k *secretServiceKeyring) prompt(path dbus.ObjectPath) (dbus.Variant, error) {
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(secretsPrompt),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := k.conn.AddMatchSignal(match...); err != nil {
+		return dbus.Variant{}, fmt.Errorf("failed to wait for the keyring prompt: %w", err)
+	}
+	defer k.conn.RemoveMatchSignal(match...)
+
+	signals := make(chan *dbus.Signal, 1)
+	k.conn.Signal(signals)
+	defer k.conn.RemoveSignal(signals)
+
+	if err := k.conn.Object(secretsBusName, path).Call(secretsPrompt+".Prompt", 0, "").Err; err != nil {
+		return dbus.Variant{}, fmt.Errorf("failed to show the keyring prompt: %w", err)
+	}
+
+	timeout := time.After(secretPromptTimeout)
+	for {
+		select {
+		case signal := <-signals:
+			if signal.Path != path || signal.Name != secretsPrompt+".Completed" || len(signal.Body) < 2 {
+				continue
+			}
+			if dismissed, _ := signal.Body[0].(bool); dismissed {
+				return dbus.Variant{}, fmt.Errorf("the keyring prompt was dismissed")
+			}
+			result, _ := signal.Body[1].(dbus.Variant)
+			return result, nil
+		case <-timeout:
+			return dbus.Variant{}, fmt.Errorf("timed out waiting for the keyring prompt")
+		}
+	}
+}
+
+func (k *secretServiceKeyring) close() {
+	k.conn.Object(secretsBusName, k.session).Call("org.freedesktop.Secret.Session.Close", 0)
+	k.conn.Close()
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package security
+
+func openKeyring() (keyring, error) {
+	return nil, errNoKeyring
+}
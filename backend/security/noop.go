@@ -0,0 +1,15 @@
+package security
+
+// NoopProtector stores values as-is. It exists for tests and tooling where
+// real encryption would get in the way; never use it for user data.
+type NoopProtector struct{}
+
+func (NoopProtector) Name() string { return "none" }
+
+func (NoopProtector) Protect(plaintext []byte) ([]byte, error) {
+	return plaintext, nil
+}
+
+func (NoopProtector) Unprotect(ciphertext []byte) ([]byte, error) {
+	return ciphertext, nil
+}
@@ -0,0 +1,93 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Key derivation parameters (scrypt, as recommended for interactive logins).
+const (
+	SaltSize = 16
+	keySize  = 32
+	scryptN  = 1 << 15
+	scryptR  = 8
+	scryptP  = 1
+)
+
+// AESGCMProtector encrypts values with AES-256-GCM under a fixed key,
+// typically derived from a passphrase with DeriveKey.
+type AESGCMProtector struct {
+	name string
+	aead cipher.AEAD
+}
+
+// NewAESGCMProtector returns a protector using key (32 bytes). name is
+// recorded in sealed values; use different names for different keys.
+func NewAESGCMProtector(name string, key []byte) (*AESGCMProtector, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCMProtector{name: name, aead: aead}, nil
+}
+
+// NewPassphraseProtector derives a key from passphrase and salt and returns
+// an AES-GCM protector using it.
+func NewPassphraseProtector(name string, passphrase string, salt []byte) (*AESGCMProtector, error) {
+	key, err := DeriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return NewAESGCMProtector(name, key)
+}
+
+// DeriveKey stretches passphrase into a 32-byte key with scrypt.
+func DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+}
+
+// NewSalt returns a fresh random salt for DeriveKey.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func (p *AESGCMProtector) Name() string { return p.name }
+
+// Protect returns nonce || ciphertext.
+func (p *AESGCMProtector) Protect(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return p.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (p *AESGCMProtector) Unprotect(ciphertext []byte) ([]byte, error) {
+	size := p.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("ciphertext too short")
+	}
+	return p.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
@@ -0,0 +1,67 @@
+package security
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks a value produced by Seal. The protector name follows,
+// then the base64 ciphertext: "enc:<protector>:<base64>".
+const sealedPrefix = "enc:"
+
+// Protector encrypts secrets (login tokens) before they are written to disk.
+// Implementations must be safe for concurrent use.
+type Protector interface {
+	// Name identifies the protector in sealed values, so a token is only
+	// ever handed back to the protector that encrypted it.
+	Name() string
+	Protect(plaintext []byte) ([]byte, error)
+	Unprotect(ciphertext []byte) ([]byte, error)
+}
+
+// IsSealed reports whether value was produced by Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// Seal encrypts value with p and encodes it as a self-describing string.
+// Empty and already sealed values are returned unchanged.
+func Seal(p Protector, value string) (string, error) {
+	if value == "" || IsSealed(value) {
+		return value, nil
+	}
+
+	ciphertext, err := p.Protect([]byte(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt with %s: %w", p.Name(), err)
+	}
+	return sealedPrefix + p.Name() + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Unseal decrypts a value produced by Seal. Plaintext values (from files
+// written before encryption existed) are returned unchanged.
+func Unseal(p Protector, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	name, encoded, ok := strings.Cut(strings.TrimPrefix(value, sealedPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	if name != p.Name() {
+		return "", fmt.Errorf("value was encrypted with %q, but %q is active", name, p.Name())
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+
+	plaintext, err := p.Unprotect(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt with %s: %w", p.Name(), err)
+	}
+	return string(plaintext), nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,11 +15,20 @@ import (
 )
 
 // AuthService handles login/session related operations.
-type AuthService struct{}
+type AuthService struct {
+	sessionStore *SessionStore
+	processes    helper.ProcessController
+}
 
 // Constructor
-func NewAuthService() *AuthService {
-	return &AuthService{}
+func NewAuthService(sessionStore *SessionStore) *AuthService {
+	return NewAuthServiceWithController(sessionStore, helper.NewProcessController())
+}
+
+// NewAuthServiceWithController creates an AuthService that stops and starts
+// the launcher through pc.
+func NewAuthServiceWithController(sessionStore *SessionStore, pc helper.ProcessController) *AuthService {
+	return &AuthService{sessionStore: sessionStore, processes: pc}
 }
 
 // GetCurrentLoginSession reads Epic's session file and returns
@@ -41,7 +51,7 @@ func (a *AuthService) GetCurrentLoginSession() (*models.LoginSession, error) {
 		return nil, fmt.Errorf("no token found")
 	}
 	loginToken := strings.TrimSpace(match[1])
-	if len(loginToken) < 1000 {
+	if len(loginToken) < minLoginTokenLength {
 		return nil, fmt.Errorf("no valid login token found (user likely logged out)")
 	}
 
@@ -64,13 +74,13 @@ func (a *AuthService) DetectNewLoginSession() (*models.LoginSession, error) {
 	}
 
 	// --- Check against stored sessions ---
-	store := NewSessionStore()
-	sessions, _ := store.LoadSessions()
-	for _, s := range sessions {
-		if s.UserID == session.UserID {
-			// Already known → no need to prompt
-			return nil, nil
-		}
+	known, err := a.sessionStore.hasSession(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if known {
+		// Already known → no need to prompt
+		return nil, nil
 	}
 
 	// Return new session
@@ -78,20 +88,16 @@ func (a *AuthService) DetectNewLoginSession() (*models.LoginSession, error) {
 }
 
 func (a *AuthService) CheckIfSessionIsNew(userID string) (bool, error) {
-	store := NewSessionStore()
-	sessions, _ := store.LoadSessions()
-	for _, s := range sessions {
-		if s.UserID == userID {
-			return false, nil // already known
-		}
+	known, err := a.sessionStore.hasSession(userID)
+	if err != nil {
+		return false, err
 	}
 
-	return true, nil
+	return !known, nil
 }
 
 func (a *AuthService) AddDetectedSession(session models.LoginSession) error {
-	store := NewSessionStore()
-	if err := store.addOrUpdate(session); err != nil {
+	if err := a.sessionStore.addOrUpdate(sourceAuthService, session); err != nil {
 		return fmt.Errorf("failed to persist session: %w", err)
 	}
 	fmt.Println("✅ User accepted and session added:", session.UserID)
@@ -99,52 +105,30 @@ func (a *AuthService) AddDetectedSession(session models.LoginSession) error {
 }
 
 // MoveAsideActiveSession stops the Epic Games Launcher, clears its login session,
-// and re-launches it for the user to sign in again.
+// and re-launches it for the user to sign in again. The switch hooks run
+// around it with the account being moved aside.
 func (a *AuthService) MoveAsideActiveSession() error {
-	fmt.Println("Stopping Epic Games Launcher...")
-
-	// 1️⃣ Kill the Epic Games Launcher process
-	killCmd := helper.NewCommand("taskkill", "/IM", "EpicGamesLauncher.exe", "/F")
+	hooks := a.sessionStore.settings.switchHooks()
+	active := a.activeStoredSession()
+	if _, err := hooks.runPre(context.Background(), hookActionMoveAside, active); err != nil {
+		return err
+	}
 
-	// CombinedOutput runs the command and returns both standard output and standard error.
-	// This is crucial because taskkill writes specific messages to stderr/stdout depending on the outcome.
-	output, err := killCmd.CombinedOutput()
+	fmt.Println("Stopping Epic Games Launcher...")
 
+	// 1️⃣ Kill the Epic Games Launcher process and confirm it's actually gone
+	launcherWasRunning, err := closeLauncher(context.Background(), a.processes)
 	if err != nil {
-		// If err is not nil, the command exited with a non-zero status (failed).
-		// However, a failure can happen simply because the process wasn't running to begin with.
-		outputStr := string(output)
-
-		if strings.Contains(strings.ToLower(outputStr), "not found") {
-			// Process not running, which is fine.
-		} else {
-			fmt.Printf("Error closing Epic Games Launcher: %v\nOutput: %s\n", err, outputStr)
-			return fmt.Errorf("failed to close Epic Games Launcher: %w", err)
-		}
-	} else {
-		fmt.Println("Epic Games Launcher closed.")
+		fmt.Printf("Error closing Epic Games Launcher: %v\n", err)
+		return err
 	}
-
-	// 2️⃣ Confirm process is actually gone (poll until no longer found)
-	const maxWait = 8 * time.Second
-	start := time.Now()
-
-	for {
-		checkCmd := helper.NewCommand("tasklist", "/FI", "IMAGENAME eq EpicGamesLauncher.exe")
-		output, _ := checkCmd.Output()
-		if !strings.Contains(string(output), "EpicGamesLauncher.exe") {
-			break // fully stopped
-		}
-		if time.Since(start) > maxWait {
-			fmt.Println("Timeout waiting for Epic Games Launcher to close.")
-			return fmt.Errorf("timeout waiting for Epic Games Launcher to close")
-		}
-		time.Sleep(250 * time.Millisecond)
+	if launcherWasRunning {
+		fmt.Println("Epic Games Launcher closed.")
 	}
 
 	fmt.Println("Confirmed Epic Games Launcher process has exited")
 
-	// 3️⃣ Disable auto-login in the session file, without touching any other
+	// 2️⃣ Disable auto-login in the session file, without touching any other
 	// launcher settings stored in the same ini file.
 	iniPath := utils.GetEpicLoginSessionPath()
 	if iniPath == "" {
@@ -163,7 +147,7 @@ func (a *AuthService) MoveAsideActiveSession() error {
 		return fmt.Errorf("failed to clear session file: %w", err)
 	}
 
-	// 4️⃣ Re-launch the Epic Games Launcher
+	// 3️⃣ Re-launch the Epic Games Launcher
 	fmt.Println("Re-launching Epic Games Launcher...")
 	launcherPath := utils.GetEpicLauncherPath()
 
@@ -173,17 +157,33 @@ func (a *AuthService) MoveAsideActiveSession() error {
 		return fmt.Errorf("launcher executable not found at %s: %w", launcherPath, err)
 	}
 
-	startCmd := helper.NewCommand(launcherPath)
-
-	if err := startCmd.Start(); err != nil {
+	if err := a.processes.Start(launcherPath); err != nil {
 		fmt.Printf("Error launching Epic Games Launcher: %v\n", err)
 		return fmt.Errorf("failed to launch Epic Games Launcher: %w", err)
 	}
 
 	fmt.Println("Epic Games Launcher started.")
+	hooks.runPost(context.Background(), hookActionMoveAside, active)
 	return nil
 }
 
+// activeStoredSession returns the signed-in account, with its stored alias
+// and username when it is a saved account, or nil if nobody is signed in.
+func (a *AuthService) activeStoredSession() *models.LoginSession {
+	current, err := a.GetCurrentLoginSession()
+	if err != nil || current == nil {
+		return nil
+	}
+	sessions, err := a.sessionStore.LoadSessions()
+	if err != nil {
+		return current
+	}
+	if stored := findSession(sessions, current.UserID); stored != nil {
+		return stored
+	}
+	return current
+}
+
 // Compares the current session token with the stored one,
 // and updates it in login_sessions.json if it's different.
 func (a *AuthService) CheckAndRenewLoginToken() (bool, error) {
@@ -192,29 +192,15 @@ func (a *AuthService) CheckAndRenewLoginToken() (bool, error) {
 		return false, err
 	}
 
-	store := NewSessionStore()
-	sessions, _ := store.LoadSessions()
-
-	for i, s := range sessions {
-		if s.UserID == session.UserID {
-			// same user found
-			if s.LoginToken != session.LoginToken {
-				// token changed → update it
-				sessions[i].LoginToken = session.LoginToken
-				sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-
-				if err := store.SaveSessions(sessions); err != nil {
-					return false, fmt.Errorf("failed to update session token: %w", err)
-				}
-
-				fmt.Println("🔄 Login token renewed for:", session.UserID)
-				return true, nil
-			}
-			return false, nil // token same → nothing to do
-		}
+	renewed, err := a.sessionStore.renewLoginToken(sourceAuthService, session.UserID, session.LoginToken)
+	if err != nil {
+		return false, fmt.Errorf("failed to update session token: %w", err)
+	}
+	if renewed {
+		fmt.Println("🔄 Login token renewed for:", session.UserID)
 	}
 
-	return false, nil // not stored, so nothing to renew
+	return renewed, nil
 }
 
 // Finds the most recent file inside Epic's Data folder
@@ -224,10 +210,22 @@ func getCurrentUserIDFromDataFolder() (string, error) {
 	if dataPath == "" {
 		return "", fmt.Errorf("could not resolve Epic Data path")
 	}
+	return userIDFromDataFolder(dataPath)
+}
+
+// userIDFromDataFolder does the lookup for getCurrentUserIDFromDataFolder on
+// any launcher Data folder, e.g. one copied from another machine.
+func userIDFromDataFolder(dataPath string) (string, error) {
+	userID, _, err := newestDataFileUser(dataPath)
+	return userID, err
+}
 
+// newestDataFileUser returns the user ID named by the most recently written
+// file in dataPath, along with when it was written.
+func newestDataFileUser(dataPath string) (string, time.Time, error) {
 	entries, err := os.ReadDir(dataPath)
 	if err != nil {
-		return "", fmt.Errorf("cannot read Epic Data folder: %w", err)
+		return "", time.Time{}, fmt.Errorf("cannot read Epic Data folder: %w", err)
 	}
 
 	var latestFile os.DirEntry
@@ -249,7 +247,7 @@ func getCurrentUserIDFromDataFolder() (string, error) {
 	}
 
 	if latestFile == nil {
-		return "", fmt.Errorf("no files found in Epic Data folder")
+		return "", time.Time{}, fmt.Errorf("no files found in Epic Data folder")
 	}
 
 	filename := latestFile.Name()
@@ -259,8 +257,8 @@ func getCurrentUserIDFromDataFolder() (string, error) {
 	nameOnly = strings.TrimPrefix(nameOnly, "OC_")
 
 	if nameOnly == "" {
-		return "", fmt.Errorf("could not extract user ID from file: %s", filename)
+		return "", time.Time{}, fmt.Errorf("could not extract user ID from file: %s", filename)
 	}
 
-	return nameOnly, nil
+	return nameOnly, latestModTime, nil
 }
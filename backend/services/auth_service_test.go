@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+func TestMoveAsideActiveSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		terminateErr error
+		wantErr      bool
+		wantStarts   int
+		wantCleared  bool
+	}{
+		{name: "clears the session and relaunches", wantStarts: 1, wantCleared: true},
+		{name: "kill failure leaves the session", terminateErr: errors.New("access denied"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newSwitchTestEnv(t)
+			launcherPath := utils.GetEpicLauncherPath()
+			if err := os.MkdirAll(filepath.Dir(launcherPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(launcherPath, nil, 0755); err != nil {
+				t.Fatal(err)
+			}
+			e.processes.TerminateErr = tt.terminateErr
+
+			auth := NewAuthServiceWithController(e.store, e.processes)
+			err := auth.MoveAsideActiveSession()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error: %v", err, tt.wantErr)
+			}
+			if starts := len(e.processes.Started()); starts != tt.wantStarts {
+				t.Errorf("launcher started %d times, want %d", starts, tt.wantStarts)
+			}
+
+			ini := e.readIni(t)
+			if cleared := strings.Contains(ini, "Enable=False\nData=\n"); cleared != tt.wantCleared {
+				t.Errorf("session cleared = %v, want %v:\n%s", cleared, tt.wantCleared, ini)
+			}
+			if !strings.Contains(ini, "[Preferences]\nTheme=Dark") {
+				t.Errorf("session file lost its other settings:\n%s", ini)
+			}
+		})
+	}
+}
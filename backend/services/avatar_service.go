@@ -41,10 +41,10 @@ type ImageMetadata struct {
 	ContentType string `json:"contentType"`
 }
 
-// NewAvatarService creates a new AvatarService instance.
-func NewAvatarService() *AvatarService {
+// NewAvatarService creates a new AvatarService instance backed by the shared session store.
+func NewAvatarService(sessionStore *SessionStore) *AvatarService {
 	return &AvatarService{
-		sessionStore: NewSessionStore(),
+		sessionStore: sessionStore,
 	}
 }
 
@@ -130,7 +130,7 @@ func (a *AvatarService) SelectAndSaveAvatar(userID string) (string, error) {
 	}
 
 	// 6. Update session store
-	if err := a.sessionStore.UpdateAvatarImage(userID, destFilename); err != nil {
+	if err := a.sessionStore.setAvatarImage(sourceAvatar, userID, destFilename); err != nil {
 		return "", fmt.Errorf("failed to update session store: %w", err)
 	}
 
@@ -220,7 +220,7 @@ func (a *AvatarService) SaveAvatarWithCrop(userID string, sourcePath string, x,
 	fmt.Printf("[AvatarService] Generated manual thumbnail: %s\n", thumbFilename)
 
 	// 3. Update session store
-	if err := a.sessionStore.UpdateAvatarImage(userID, finalFilename); err != nil {
+	if err := a.sessionStore.setAvatarImage(sourceAvatar, userID, finalFilename); err != nil {
 		return "", fmt.Errorf("failed to update session store: %w", err)
 	}
 
@@ -296,7 +296,7 @@ func (a *AvatarService) SetAvatar(userID string, filename string) error {
 		return fmt.Errorf("avatar file not found: %s", filename)
 	}
 
-	return a.sessionStore.UpdateAvatarImage(userID, filename)
+	return a.sessionStore.setAvatarImage(sourceAvatar, userID, filename)
 }
 
 // SetAvatarColor sets a custom background color for the given userID in the session store.
@@ -305,7 +305,7 @@ func (a *AvatarService) SetAvatarColor(userID string, color string) error {
 		return fmt.Errorf("userID is required")
 	}
 
-	return a.sessionStore.UpdateAvatarColor(userID, color)
+	return a.sessionStore.setAvatarColor(sourceAvatar, userID, color)
 }
 
 // RemoveAvatar clears the custom avatar for the given userID in the session store.
@@ -314,7 +314,32 @@ func (a *AvatarService) RemoveAvatar(userID string) error {
 		return fmt.Errorf("userID is required")
 	}
 
-	return a.sessionStore.UpdateAvatarImage(userID, "")
+	return a.sessionStore.setAvatarImage(sourceAvatar, userID, "")
+}
+
+// BatchSetAvatar assigns the same avatar file to every listed account in one go.
+func (a *AvatarService) BatchSetAvatar(userIDs []string, filename string) (*BatchReport, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("filename is required")
+	}
+
+	// Verify the file exists
+	avatarPath := filepath.Join(a.sessionStore.GetAvatarDir(), filename)
+	if _, err := os.Stat(avatarPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("avatar file not found: %s", filename)
+	}
+
+	return a.sessionStore.batchSetAvatarImage(sourceAvatar, userIDs, filename)
+}
+
+// BatchSetAvatarColor sets the same background color for every listed account in one go.
+func (a *AvatarService) BatchSetAvatarColor(userIDs []string, color string) (*BatchReport, error) {
+	return a.sessionStore.batchSetAvatarColor(sourceAvatar, userIDs, color)
+}
+
+// BatchRemoveAvatar clears the custom avatar of every listed account in one go.
+func (a *AvatarService) BatchRemoveAvatar(userIDs []string) (*BatchReport, error) {
+	return a.sessionStore.batchSetAvatarImage(sourceAvatar, userIDs, "")
 }
 
 // DeleteAvatarFile deletes the specified avatar file and its thumbnail from the disk.
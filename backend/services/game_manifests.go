@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// installedGame is the part of a launcher .item manifest this app uses.
+type installedGame struct {
+	AppName          string `json:"AppName"`
+	DisplayName      string `json:"DisplayName"`
+	InstallLocation  string `json:"InstallLocation"`
+	LaunchExecutable string `json:"LaunchExecutable"`
+	Incomplete       bool   `json:"bIsIncompleteInstall"`
+}
+
+// readInstalledGames reads every .item manifest in dir. Unreadable
+// manifests are skipped; a missing folder means nothing is installed.
+func readInstalledGames(dir string) ([]installedGame, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.item"))
+	if err != nil {
+		return nil, err
+	}
+
+	games := []installedGame{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var game installedGame
+		if err := json.Unmarshal(data, &game); err != nil || game.AppName == "" {
+			fmt.Printf("⚠️ Skipping unreadable game manifest %s\n", filepath.Base(path))
+			continue
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
+
+// findInstalledGame looks a game up by its AppName, case-insensitively.
+func findInstalledGame(games []installedGame, appName string) *installedGame {
+	for i := range games {
+		if strings.EqualFold(games[i].AppName, appName) {
+			return &games[i]
+		}
+	}
+	return nil
+}
+
+// name returns the game's display name, or its AppName if it has none.
+func (g installedGame) name() string {
+	return firstNonEmpty(g.DisplayName, g.AppName)
+}
+
+// launchImageName returns the image name of the manifest's launch
+// executable, which is what counts as the game running, or "" if the
+// manifest doesn't name one.
+func (g installedGame) launchImageName() string {
+	if g.LaunchExecutable == "" {
+		return ""
+	}
+	return path.Base(strings.ReplaceAll(g.LaunchExecutable, `\`, "/"))
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"epic-games-account-switcher/backend/security"
+	"epic-games-account-switcher/backend/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// masterProtectorName tags tokens sealed with the master-password key.
+const masterProtectorName = "master"
+
+// lockCheckValue is sealed with the master key and stored, so a password can
+// be verified by decrypting it (AES-GCM rejects a wrong key).
+const lockCheckValue = "epic-switcher-lock-check"
+
+// Failed-unlock rate limiting: after freeAttempts failures, each further
+// failure doubles the wait, up to maxLockout.
+const (
+	freeAttempts = 5
+	baseLockout  = 30 * time.Second
+	maxLockout   = 15 * time.Minute
+)
+
+// EventAppLocked is emitted when the app locks, by Lock or after being
+// idle, and EventAppUnlocked once Unlock succeeds, so the frontend can show
+// and hide its lock screen.
+const (
+	EventAppLocked   = "app:locked"
+	EventAppUnlocked = "app:unlocked"
+)
+
+// LockStatus is returned to the frontend to drive the lock screen.
+type LockStatus struct {
+	Enabled           bool `json:"enabled"`
+	Locked            bool `json:"locked"`
+	IdleMinutes       int  `json:"idleMinutes"`
+	RetryAfterSeconds int  `json:"retryAfterSeconds"`
+}
+
+// lockConfig is persisted in app_lock.json. It holds no key material, only
+// what's needed to derive and verify the key from the password.
+type lockConfig struct {
+	Enabled        bool      `json:"enabled"`
+	Salt           []byte    `json:"salt,omitempty"`
+	Check          string    `json:"check,omitempty"`
+	IdleMinutes    int       `json:"idleMinutes"`
+	FailedAttempts int       `json:"failedAttempts"`
+	LockedUntil    time.Time `json:"lockedUntil,omitempty"`
+}
+
+// LockService guards the app with an optional master password. While locked,
+// sessions can't be listed or switched, and the key that decrypts login
+// tokens exists only in memory between Unlock and Lock.
+type LockService struct {
+	ctx          context.Context
+	sessionStore *SessionStore
+	configPath   string
+
+	mu           sync.Mutex
+	config       lockConfig
+	locked       bool
+	lastActivity time.Time
+	stopIdle     chan struct{}
+}
+
+// NewLockService creates the lock service and, if a master password is set,
+// locks the session store straight away.
+func NewLockService(sessionStore *SessionStore) *LockService {
+	l := &LockService{
+		sessionStore: sessionStore,
+		configPath:   filepath.Join(utils.GetAppDataPath(), "app_lock.json"),
+	}
+
+	if err := l.loadConfig(); err != nil {
+		fmt.Printf("⚠️ Failed to read app lock settings: %v\n", err)
+	}
+	if l.config.Enabled {
+		l.locked = true
+		l.sessionStore.lockTokens()
+	}
+
+	return l
+}
+
+// setContext sets the context for the service (unexported to hide from Wails bindings).
+func (l *LockService) setContext(ctx context.Context) {
+	l.ctx = ctx
+}
+
+// SetLockServiceContext provides a way for other packages to set the context
+// without exposing it to the frontend bindings. It also starts the idle timer.
+func SetLockServiceContext(l *LockService, ctx context.Context) {
+	l.setContext(ctx)
+	l.startIdleWatch()
+}
+
+// GetLockStatus reports whether the lock is enabled and currently engaged.
+func (l *LockService) GetLockStatus() LockStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return LockStatus{
+		Enabled:           l.config.Enabled,
+		Locked:            l.locked,
+		IdleMinutes:       l.config.IdleMinutes,
+		RetryAfterSeconds: l.retryAfterSeconds(),
+	}
+}
+
+// EnableLock sets a master password and re-encrypts every stored token with
+// a key derived from it. idleMinutes of 0 disables auto-lock.
+func (l *LockService) EnableLock(password string, idleMinutes int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.config.Enabled {
+		return fmt.Errorf("a master password is already set")
+	}
+	if idleMinutes < 0 {
+		return fmt.Errorf("idle timeout can't be negative")
+	}
+
+	protector, config, err := newMasterProtector(password)
+	if err != nil {
+		return err
+	}
+	config.IdleMinutes = idleMinutes
+	if err := l.switchProtector(protector, config); err != nil {
+		return err
+	}
+
+	l.lastActivity = time.Now()
+	fmt.Println("🔐 Master password enabled.")
+	return nil
+}
+
+// ChangePassword re-encrypts every stored token under a new master password.
+func (l *LockService) ChangePassword(currentPassword string, newPassword string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked {
+		return ErrAppLocked
+	}
+	if _, err := l.verifyPassword(currentPassword); err != nil {
+		return err
+	}
+
+	protector, config, err := newMasterProtector(newPassword)
+	if err != nil {
+		return err
+	}
+	config.IdleMinutes = l.config.IdleMinutes
+	return l.switchProtector(protector, config)
+}
+
+// DisableLock removes the master password, moving tokens back to the
+// platform protector.
+func (l *LockService) DisableLock(password string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked {
+		return ErrAppLocked
+	}
+	if _, err := l.verifyPassword(password); err != nil {
+		return err
+	}
+
+	config := lockConfig{IdleMinutes: l.config.IdleMinutes}
+	if err := l.switchProtector(defaultProtector(utils.GetAppDataPath()), config); err != nil {
+		return err
+	}
+
+	fmt.Println("🔓 Master password removed.")
+	return nil
+}
+
+// Unlock verifies password and makes sessions available again.
+func (l *LockService) Unlock(password string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.config.Enabled || !l.locked {
+		return nil
+	}
+
+	protector, err := l.verifyPassword(password)
+	if err != nil {
+		return err
+	}
+
+	l.sessionStore.unlockTokens(protector)
+	l.locked = false
+	l.lastActivity = time.Now()
+	if l.ctx != nil {
+		runtime.EventsEmit(l.ctx, EventAppUnlocked)
+	}
+	fmt.Println("🔓 App unlocked.")
+	return nil
+}
+
+// Lock engages the lock immediately, dropping the token key from memory.
+func (l *LockService) Lock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.config.Enabled {
+		return fmt.Errorf("no master password is set")
+	}
+	l.lockLocked()
+	return nil
+}
+
+// SetIdleTimeout changes how many idle minutes trigger auto-lock (0 = never).
+func (l *LockService) SetIdleTimeout(minutes int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if minutes < 0 {
+		return fmt.Errorf("idle timeout can't be negative")
+	}
+	config := l.config
+	config.IdleMinutes = minutes
+	return l.saveConfig(config)
+}
+
+// ReportActivity resets the idle timer. The frontend calls this on user input;
+// background polling deliberately doesn't count as activity.
+func (l *LockService) ReportActivity() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastActivity = time.Now()
+}
+
+// switchProtector re-encrypts every token with protector and then saves
+// config. If the config can't be saved, the tokens are moved back so they
+// always match what's on disk. Callers must hold l.mu.
+func (l *LockService) switchProtector(protector security.Protector, config lockConfig) error {
+	prev := l.sessionStore.activeProtector()
+	if err := l.sessionStore.resealTokens(protector); err != nil {
+		return fmt.Errorf("failed to re-encrypt tokens: %w", err)
+	}
+
+	if err := l.saveConfig(config); err != nil {
+		if rollbackErr := l.sessionStore.resealTokens(prev); rollbackErr != nil {
+			fmt.Printf("⚠️ Failed to roll back token encryption: %v\n", rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// lockLocked engages the lock. Callers must hold l.mu.
+func (l *LockService) lockLocked() {
+	if l.locked {
+		return
+	}
+	l.sessionStore.lockTokens()
+	l.locked = true
+	if l.ctx != nil {
+		runtime.EventsEmit(l.ctx, EventAppLocked)
+	}
+	fmt.Println("🔒 App locked.")
+}
+
+// verifyPassword checks password against the stored check value, applying
+// the failed-attempt rate limit. On success it returns the master protector.
+// Callers must hold l.mu.
+func (l *LockService) verifyPassword(password string) (security.Protector, error) {
+	if !l.config.Enabled {
+		return nil, fmt.Errorf("no master password is set")
+	}
+	if wait := l.retryAfterSeconds(); wait > 0 {
+		return nil, fmt.Errorf("too many failed attempts, try again in %d seconds", wait)
+	}
+
+	protector, err := security.NewPassphraseProtector(masterProtectorName, password, l.config.Salt)
+	if err == nil {
+		var check string
+		if check, err = security.Unseal(protector, l.config.Check); err == nil && check != lockCheckValue {
+			err = errors.New("check value mismatch")
+		}
+	}
+
+	config := l.config
+	if err != nil {
+		config.FailedAttempts++
+		if config.FailedAttempts >= freeAttempts {
+			config.LockedUntil = time.Now().Add(lockoutFor(config.FailedAttempts))
+		}
+		if saveErr := l.saveConfig(config); saveErr != nil {
+			fmt.Printf("⚠️ Failed to record failed unlock attempt: %v\n", saveErr)
+		}
+		return nil, fmt.Errorf("incorrect password")
+	}
+
+	if config.FailedAttempts > 0 {
+		config.FailedAttempts = 0
+		config.LockedUntil = time.Time{}
+		if saveErr := l.saveConfig(config); saveErr != nil {
+			fmt.Printf("⚠️ Failed to reset failed unlock attempts: %v\n", saveErr)
+		}
+	}
+	return protector, nil
+}
+
+// lockoutFor returns how long to refuse attempts after the given number of failures.
+func lockoutFor(failures int) time.Duration {
+	wait := baseLockout
+	for i := freeAttempts; i < failures && wait < maxLockout; i++ {
+		wait *= 2
+	}
+	return min(wait, maxLockout)
+}
+
+// retryAfterSeconds returns the remaining rate-limit wait. Callers must hold l.mu.
+func (l *LockService) retryAfterSeconds() int {
+	remaining := time.Until(l.config.LockedUntil)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(remaining.Seconds()) + 1
+}
+
+// newMasterProtector derives a fresh master key from password and returns it
+// with a config carrying the new salt and check value.
+func newMasterProtector(password string) (security.Protector, lockConfig, error) {
+	if len(password) < 4 {
+		return nil, lockConfig{}, fmt.Errorf("password must be at least 4 characters")
+	}
+
+	salt, err := security.NewSalt()
+	if err != nil {
+		return nil, lockConfig{}, err
+	}
+	protector, err := security.NewPassphraseProtector(masterProtectorName, password, salt)
+	if err != nil {
+		return nil, lockConfig{}, err
+	}
+	check, err := security.Seal(protector, lockCheckValue)
+	if err != nil {
+		return nil, lockConfig{}, err
+	}
+
+	return protector, lockConfig{Enabled: true, Salt: salt, Check: check}, nil
+}
+
+// startIdleWatch locks the app once it has been idle for the configured time.
+func (l *LockService) startIdleWatch() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopIdle != nil {
+		return
+	}
+	l.stopIdle = make(chan struct{})
+	l.lastActivity = time.Now()
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				l.checkIdle()
+			}
+		}
+	}(l.stopIdle)
+}
+
+func (l *LockService) checkIdle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.config.Enabled || l.locked || l.config.IdleMinutes == 0 {
+		return
+	}
+	if time.Since(l.lastActivity) < time.Duration(l.config.IdleMinutes)*time.Minute {
+		return
+	}
+
+	l.lockLocked()
+}
+
+func (l *LockService) loadConfig() error {
+	data, err := os.ReadFile(l.configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &l.config)
+}
+
+// saveConfig persists config and makes it current. Callers must hold l.mu.
+func (l *LockService) saveConfig(config lockConfig) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.configPath), 0755); err != nil {
+		return err
+	}
+	if err := utils.WriteFileAtomic(l.configPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to save app lock settings: %w", err)
+	}
+	l.config = config
+	return nil
+}
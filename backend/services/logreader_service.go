@@ -8,7 +8,6 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"time"
 
 	"epic-games-account-switcher/backend/utils"
 )
@@ -20,12 +19,13 @@ const maxRecentLogFiles = 3
 const epicLaunchMarker = "FCommunityPortalLaunchAppTask: Preparing to launch app"
 
 type LogReaderService struct {
-	LogsDir string
+	LogsDir      string
+	sessionStore *SessionStore
 }
 
 // constructor
-func NewLogReaderService() *LogReaderService {
-	return &LogReaderService{LogsDir: utils.GetEpicLogsPath()}
+func NewLogReaderService(sessionStore *SessionStore) *LogReaderService {
+	return &LogReaderService{LogsDir: utils.GetEpicLogsPath(), sessionStore: sessionStore}
 }
 
 // SyncUsernames checks the sessions file and fills in missing usernames
@@ -34,8 +34,7 @@ func NewLogReaderService() *LogReaderService {
 func (l *LogReaderService) SyncUsernames(isDeepSearch bool) (bool, error) {
 
 	// 1. Load sessions from JSON file
-	store := NewSessionStore()
-	sessions, err := store.LoadSessions()
+	sessions, err := l.sessionStore.LoadSessions()
 	if err != nil {
 		return false, fmt.Errorf("failed to load sessions: %w", err)
 	}
@@ -81,57 +80,22 @@ func (l *LogReaderService) SyncUsernames(isDeepSearch bool) (bool, error) {
 	fmt.Printf("🔍 Using %d log file(s) for username sync.\n", len(logFiles))
 	fmt.Printf("🔍 Searching in log files: %v\n", logFiles)
 
-	// 7. Prepare regex patterns
-	usernamePattern := regexp.MustCompile(`-epicusername="([^"]+)"`)
-	userIdPattern := regexp.MustCompile(`-epicuserid=([a-f0-9]+)`)
-
-	// 8. Build userId → username map from logs
-	found := map[string]string{}
-
-	for _, path := range logFiles {
-		file, err := os.Open(path)
-		if err != nil {
-			continue
-		}
-		defer file.Close()
-
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if !strings.Contains(line, epicLaunchMarker) {
-				continue
-			}
-			usernameMatch := usernamePattern.FindStringSubmatch(line)
-			userIdMatch := userIdPattern.FindStringSubmatch(line)
-			if len(usernameMatch) > 1 && len(userIdMatch) > 1 {
-				found[userIdMatch[1]] = usernameMatch[1]
-			}
-		}
-	}
+	// 7. Build userId → username map from logs
+	found := readLaunchedAccounts(logFiles)
 
-	// 9. If no usernames found in logs, stop here
+	// 8. If no usernames found in logs, stop here
 	if len(found) == 0 {
 		fmt.Println("ℹ️ No usernames found in logs.")
 		return false, nil
 	}
 
-	// 10. Fill in missing usernames and mark updated
-	changed := false
-	for i, s := range sessions {
-		if s.Username == "" {
-			if uname, ok := found[s.UserID]; ok {
-				sessions[i].Username = uname
-				sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-				changed = true
-			}
-		}
+	// 9. Fill in missing usernames and save if anything changed. This
+	// re-checks under the store lock, so edits made while scanning survive.
+	changed, err := l.sessionStore.fillMissingUsernames(sourceLogReader, found)
+	if err != nil {
+		return false, fmt.Errorf("failed to save updated sessions: %w", err)
 	}
-
-	// 11. Save sessions if anything changed
 	if changed {
-		if err := store.SaveSessions(sessions); err != nil {
-			return false, fmt.Errorf("failed to save updated sessions: %w", err)
-		}
 		fmt.Println("✅ Missing usernames filled from logs.")
 		return true, nil
 	}
@@ -183,3 +147,34 @@ func (l *LogReaderService) GetUsernameForUserID(userID string) (string, error) {
 
 	return "", fmt.Errorf("username not found for userID %s", userID)
 }
+
+// readLaunchedAccounts scans launcher logs for app launches and returns the
+// userId → username pairs they mention. The last username seen for a userId
+// is kept, so a rename later in a log wins over the launches before it.
+func readLaunchedAccounts(logFiles []string) map[string]string {
+	usernamePattern := regexp.MustCompile(`-epicusername="([^"]+)"`)
+	userIdPattern := regexp.MustCompile(`-epicuserid=([a-f0-9]+)`)
+
+	found := map[string]string{}
+	for _, path := range logFiles {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.Contains(line, epicLaunchMarker) {
+				continue
+			}
+			usernameMatch := usernamePattern.FindStringSubmatch(line)
+			userIdMatch := userIdPattern.FindStringSubmatch(line)
+			if len(usernameMatch) > 1 && len(userIdMatch) > 1 {
+				found[userIdMatch[1]] = usernameMatch[1]
+			}
+		}
+		file.Close()
+	}
+	return found
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
+)
+
+// auditFileName is the append-only journal of session changes, kept next to
+// the sessions file.
+const auditFileName = "session_audit.jsonl"
+
+// Services recorded as the source of a change in the audit journal.
+// Bound SessionStore methods are called by the frontend directly.
+const (
+	sourceSessionStore = "SessionStore"
+	sourceAuthService  = "AuthService"
+	sourceAvatar       = "AvatarService"
+	sourceLogReader    = "LogReaderService"
+	sourceSwitch       = "SwitchService"
+	sourceTransfer     = "TransferService"
+)
+
+// mutation names a store write for the audit journal: what was done and
+// which service asked for it.
+type mutation struct {
+	op     string
+	source string
+}
+
+// AuditEntry is one change to one account. Before and After hold only the
+// fields that changed; Before is empty for a new account and After for one
+// removed for good. Tokens appear only as their fingerprint.
+type AuditEntry struct {
+	Time      string         `json:"time"`
+	Operation string         `json:"operation"`
+	Source    string         `json:"source"`
+	UserID    string         `json:"userId"`
+	Before    map[string]any `json:"before,omitempty"`
+	After     map[string]any `json:"after,omitempty"`
+}
+
+// auditPath returns the path of the audit journal.
+func (s *SessionStore) auditPath() string {
+	return filepath.Join(filepath.Dir(s.filePath), auditFileName)
+}
+
+// GetAccountHistory returns the recorded changes to one account, newest first.
+func (s *SessionStore) GetAccountHistory(userID string) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked {
+		return nil, ErrAppLocked
+	}
+
+	history := []AuditEntry{}
+	f, err := os.Open(s.auditPath())
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit journal: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // A torn last line from a crash shouldn't hide the rest
+		}
+		if entry.UserID == userID {
+			history = append(history, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit journal: %w", err)
+	}
+
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
+// journal appends an entry for every account that differs between prev and
+// next. Failures are logged only: the change itself is already saved.
+// Callers must hold s.mu.
+func (s *SessionStore) journal(m mutation, prev, next *models.SessionFile) {
+	entries := auditChanges(m, prev, next)
+	if len(entries) == 0 {
+		return
+	}
+
+	f, err := os.OpenFile(s.auditPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to open audit journal: %v\n", err)
+		return
+	}
+	defer f.Close()
+
+	var buf []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if _, err := f.Write(buf); err != nil {
+		fmt.Printf("⚠️ Failed to write audit journal: %v\n", err)
+	}
+}
+
+// auditChanges compares every account in prev and next, including the
+// recycle bin, and returns one entry per account that changed.
+func auditChanges(m mutation, prev, next *models.SessionFile) []AuditEntry {
+	before := auditSnapshots(prev)
+	after := auditSnapshots(next)
+
+	userIDs := []string{}
+	for userID := range before {
+		userIDs = append(userIDs, userID)
+	}
+	for userID := range after {
+		if _, ok := before[userID]; !ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Strings(userIDs)
+
+	now := time.Now().Format(time.RFC3339)
+	entries := []AuditEntry{}
+	for _, userID := range userIDs {
+		changedBefore, changedAfter := diffFields(before[userID], after[userID])
+		if changedBefore == nil && changedAfter == nil {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Time:      now,
+			Operation: m.op,
+			Source:    m.source,
+			UserID:    userID,
+			Before:    changedBefore,
+			After:     changedAfter,
+		})
+	}
+	return entries
+}
+
+// auditSnapshots flattens every account in file to its JSON fields, with the
+// token swapped for its fingerprint. updated_at is left out; it changes with
+// every edit and the entry carries its own time.
+func auditSnapshots(file *models.SessionFile) map[string]map[string]any {
+	snapshots := map[string]map[string]any{}
+	if file == nil {
+		return snapshots
+	}
+
+	add := func(sess models.LoginSession, deletedAt string) {
+		// Tokens set during this update aren't sealed (or fingerprinted) yet
+		if sess.LoginToken != "" && !security.IsSealed(sess.LoginToken) {
+			sess.TokenFingerprint = tokenFingerprint(sess.LoginToken)
+		}
+		sess.LoginToken = ""
+
+		data, err := json.Marshal(sess)
+		if err != nil {
+			return
+		}
+		fields := map[string]any{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return
+		}
+		delete(fields, "loginToken")
+		delete(fields, "updated_at")
+		if deletedAt != "" {
+			fields["deletedAt"] = deletedAt
+		}
+		snapshots[sess.UserID] = fields
+	}
+
+	for _, sess := range file.Sessions {
+		add(sess, "")
+	}
+	for _, deleted := range file.Trash {
+		add(deleted.LoginSession, deleted.DeletedAt)
+	}
+	return snapshots
+}
+
+// diffFields returns the fields whose values differ between a and b, as seen
+// on each side. Both are nil if nothing changed.
+func diffFields(a, b map[string]any) (map[string]any, map[string]any) {
+	if a == nil && b == nil {
+		return nil, nil
+	}
+	if a == nil {
+		return nil, b
+	}
+	if b == nil {
+		return a, nil
+	}
+
+	var before, after map[string]any
+	for key, value := range a {
+		if other, ok := b[key]; !ok || !reflect.DeepEqual(value, other) {
+			if before == nil {
+				before, after = map[string]any{}, map[string]any{}
+			}
+			before[key] = value
+			if ok {
+				after[key] = other
+			}
+		}
+	}
+	for key, value := range b {
+		if _, ok := a[key]; !ok {
+			if before == nil {
+				before, after = map[string]any{}, map[string]any{}
+			}
+			after[key] = value
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// Storage backends a vault's sessions can be kept in.
+const (
+	StorageJSON = "json"
+	StorageKV   = "kv"
+)
+
+// sessionBackend is where a SessionStore keeps its sessions. The store owns
+// caching, locking, token sealing and auditing; a backend only reads and
+// writes the data. Callers must hold the store mutex.
+type sessionBackend interface {
+	// name is StorageJSON or StorageKV.
+	name() string
+	// path is the file whose modification time tells the store the data
+	// changed on disk.
+	path() string
+	load() (*models.SessionFile, error)
+	// save replaces the stored data with file.
+	save(file *models.SessionFile) error
+}
+
+// jsonBackend is the default backend: the whole store in login_sessions.json,
+// with rotating backups, corruption recovery and schema migrations.
+type jsonBackend struct {
+	store *SessionStore
+}
+
+func (b *jsonBackend) name() string { return StorageJSON }
+
+func (b *jsonBackend) path() string { return b.store.filePath }
+
+func (b *jsonBackend) load() (*models.SessionFile, error) {
+	return b.store.loadFile()
+}
+
+func (b *jsonBackend) save(file *models.SessionFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := b.store.ensureDir(); err != nil {
+		return err
+	}
+
+	// Keep the previous good version before replacing it
+	if err := b.store.rotateBackups(); err != nil {
+		fmt.Printf("⚠️ Failed to rotate session backups: %v\n", err)
+	}
+
+	return utils.WriteFileAtomic(b.store.filePath, data, 0644)
+}
+
+// backendFor picks the backend for the vault whose sessions file is
+// sessionPath: the key-value database if one was migrated to, else JSON.
+func (s *SessionStore) backendFor(sessionPath string) sessionBackend {
+	if kvPath := kvPathFor(sessionPath); fileExists(kvPath) {
+		return &kvBackend{file: kvPath}
+	}
+	return &jsonBackend{store: s}
+}
+
+// GetStorageBackend reports which backend the active vault uses.
+func (s *SessionStore) GetStorageBackend() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backend.name()
+}
+
+// MigrateStorage moves the active vault's sessions to the target backend
+// (StorageJSON or StorageKV) in one go. The new copy is written and read
+// back before the old one is renamed aside, so a failure leaves the vault
+// as it was.
+func (s *SessionStore) MigrateStorage(target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureWritable(); err != nil {
+		return err
+	}
+	if target == s.backend.name() {
+		return nil
+	}
+
+	var next sessionBackend
+	switch target {
+	case StorageJSON:
+		next = &jsonBackend{store: s}
+	case StorageKV:
+		next = &kvBackend{file: kvPathFor(s.filePath)}
+	default:
+		return fmt.Errorf("unknown storage backend: %s", target)
+	}
+
+	// 1️⃣ Read everything through the current backend
+	file, err := s.current()
+	if err != nil {
+		return err
+	}
+	file = cloneSessionFile(file)
+	if err := s.sealTokens(file); err != nil {
+		return err
+	}
+
+	// 2️⃣ Write it to the new one and check it reads back the same
+	prev := s.backend
+	if err := next.save(file); err != nil {
+		os.Remove(next.path())
+		return fmt.Errorf("failed to write %s storage: %w", target, err)
+	}
+	copied, err := next.load()
+	if err == nil && !sameSessionData(file, copied) {
+		err = fmt.Errorf("data read back doesn't match")
+	}
+	if err != nil {
+		os.Remove(next.path())
+		return fmt.Errorf("failed to verify %s storage: %w", target, err)
+	}
+
+	// 3️⃣ Move the old copy aside, so the vault only has one source of truth
+	aside := fmt.Sprintf("%s.migrated-%s", prev.path(), time.Now().Format("20060102-150405"))
+	if err := os.Rename(prev.path(), aside); err != nil {
+		os.Remove(next.path())
+		return fmt.Errorf("failed to retire %s storage: %w", prev.name(), err)
+	}
+
+	s.backend = next
+	s.setCache(copied)
+	fmt.Printf("🔄 Migrated sessions from %s to %s storage (old copy: %s)\n", prev.name(), target, filepath.Base(aside))
+	return nil
+}
+
+// sameSessionData reports whether two files hold the same sessions and
+// recycle bin, ignoring order.
+func sameSessionData(a, b *models.SessionFile) bool {
+	if len(a.Sessions) != len(b.Sessions) || len(a.Trash) != len(b.Trash) {
+		return false
+	}
+	for _, sess := range a.Sessions {
+		other := findSession(b.Sessions, sess.UserID)
+		if other == nil || !reflect.DeepEqual(normalizeTags(sess), normalizeTags(*other)) {
+			return false
+		}
+	}
+	for _, deleted := range a.Trash {
+		other := findDeleted(b.Trash, deleted.UserID)
+		if other == nil || other.DeletedAt != deleted.DeletedAt {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTags treats nil and empty tag lists as equal, since JSON
+// round-trips drop empty ones.
+func normalizeTags(sess models.LoginSession) models.LoginSession {
+	if len(sess.Tags) == 0 {
+		sess.Tags = nil
+	}
+	return sess
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
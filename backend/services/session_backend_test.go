@@ -0,0 +1,190 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
+)
+
+// newStorageTestStore returns a store in a temporary app data folder holding
+// two accounts, one of them also in the recycle bin.
+func newStorageTestStore(t *testing.T) *SessionStore {
+	t.Helper()
+
+	e := newSwitchTestEnv(t)
+	second := models.LoginSession{UserID: "user-c", Username: "PlayerC", LoginToken: testOldToken, Tags: []string{"alt"}}
+	if err := e.store.addOrUpdate(sourceSessionStore, second); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.store.DeleteSession(second.UserID); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.store.addOrUpdate(sourceSessionStore, second); err != nil {
+		t.Fatal(err)
+	}
+	return e.store
+}
+
+// sessionSummary lists the user IDs of the live and deleted sessions, so
+// two loads can be compared.
+func sessionSummary(t *testing.T, s *SessionStore) string {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, sess := range file.Sessions {
+		ids = append(ids, sess.UserID+"/"+strings.Join(sess.Tags, ","))
+	}
+	for _, deleted := range file.Trash {
+		ids = append(ids, "trash:"+deleted.UserID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, " ")
+}
+
+func assertTokens(t *testing.T, s *SessionStore, want map[string]string) {
+	t.Helper()
+
+	for userID, token := range want {
+		got, err := s.revealLoginToken(userID)
+		if err != nil {
+			t.Errorf("token of %s: %v", userID, err)
+		} else if got != token {
+			t.Errorf("token of %s = %q, want %q", userID, got, token)
+		}
+	}
+}
+
+func TestMigrateStorageRoundTrip(t *testing.T) {
+	s := newStorageTestStore(t)
+	before := sessionSummary(t, s)
+	tokens := map[string]string{"user-b": testNewToken, "user-c": testOldToken}
+
+	if err := s.MigrateStorage(StorageKV); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetStorageBackend(); got != StorageKV {
+		t.Fatalf("backend = %s, want %s", got, StorageKV)
+	}
+	if fileExists(s.filePath) {
+		t.Error("the JSON file is still in place after migrating away from it")
+	}
+	if retired, _ := filepath.Glob(s.filePath + ".migrated-*"); len(retired) != 1 {
+		t.Errorf("retired JSON copies = %q, want one", retired)
+	}
+	if got := sessionSummary(t, s); got != before {
+		t.Errorf("sessions in KV = %s, want %s", got, before)
+	}
+	assertTokens(t, s, tokens)
+
+	// Removing an account must remove its record, not leave it in the bucket
+	if err := s.DeleteSession("user-c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PurgeDeletedSession("user-c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MigrateStorage(StorageJSON); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetStorageBackend(); got != StorageJSON {
+		t.Fatalf("backend = %s, want %s", got, StorageJSON)
+	}
+	if got, want := sessionSummary(t, s), "user-b/"; got != want {
+		t.Errorf("sessions back in JSON = %s, want %s", got, want)
+	}
+	assertTokens(t, s, map[string]string{"user-b": testNewToken})
+}
+
+func TestMigrateStorageFailureLeavesVault(t *testing.T) {
+	s := newStorageTestStore(t)
+	before := sessionSummary(t, s)
+
+	// A folder where the database should go makes the write fail
+	if err := os.MkdirAll(kvPathFor(s.filePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MigrateStorage(StorageKV); err == nil {
+		t.Fatal("migration succeeded, want an error")
+	}
+
+	if got := s.GetStorageBackend(); got != StorageJSON {
+		t.Errorf("backend = %s, want %s", got, StorageJSON)
+	}
+	if !fileExists(s.filePath) {
+		t.Fatal("the JSON file was moved aside by a failed migration")
+	}
+	if retired, _ := filepath.Glob(s.filePath + ".migrated-*"); len(retired) != 0 {
+		t.Errorf("failed migration retired %q", retired)
+	}
+	if got := sessionSummary(t, s); got != before {
+		t.Errorf("sessions = %s, want %s", got, before)
+	}
+}
+
+func TestResealReachesVaultsInKV(t *testing.T) {
+	s := newStorageTestStore(t)
+	vaults := NewVaultService(s)
+	locks := NewLockService(s)
+
+	// A second vault, moved to the key-value store, with an older JSON copy
+	vault, err := vaults.CreateVault("Second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vaults.SwitchVault(vault.ID); err != nil {
+		t.Fatal(err)
+	}
+	other := models.LoginSession{UserID: "user-d", Username: "PlayerD", LoginToken: testNewToken}
+	if err := s.addOrUpdate(sourceSessionStore, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MigrateStorage(StorageKV); err != nil {
+		t.Fatal(err)
+	}
+	if err := vaults.SwitchVault(defaultVaultID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := locks.EnableLock("first password", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := locks.ChangePassword("first password", "second password"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vaults.SwitchVault(vault.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetStorageBackend(); got != StorageKV {
+		t.Fatalf("backend = %s, want %s", got, StorageKV)
+	}
+	assertTokens(t, s, map[string]string{"user-d": testNewToken})
+
+	// The retired JSON copy follows the key too
+	retired, _ := filepath.Glob(vaultSessionPath(vault.ID) + ".migrated-*")
+	if len(retired) != 1 {
+		t.Fatalf("retired JSON copies = %q, want one", retired)
+	}
+	master := s.activeProtector()
+	err = rewriteTokenValues(retired[0], func(token string) (string, error) {
+		plaintext, err := security.Unseal(master, token)
+		if err == nil && plaintext != testNewToken {
+			t.Errorf("retired copy token = %q, want %q", plaintext, testNewToken)
+		}
+		return token, err
+	})
+	if err != nil {
+		t.Errorf("retired copy isn't readable with the new password: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"os"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// maxSessionBackups is how many previous good versions of the sessions file
+// are kept as rotating .bak copies (.bak.1 is the newest).
+const maxSessionBackups = 5
+
+// backupPath returns the path of the n-th rotating backup (1 = newest).
+func (s *SessionStore) backupPath(n int) string {
+	return fmt.Sprintf("%s.bak.%d", s.filePath, n)
+}
+
+// rotateBackups shifts existing backups down by one slot and copies the
+// current sessions file into .bak.1. The current file is only backed up if
+// it still parses, so a damaged file never pushes a good backup out; it is
+// quarantined instead.
+func (s *SessionStore) rotateBackups() error {
+	current, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, _, err := parseSessionFile(current); err != nil {
+		_, err := s.quarantine(current)
+		return err
+	}
+
+	// Drop the oldest, then shift .bak.(n-1) → .bak.n
+	os.Remove(s.backupPath(maxSessionBackups))
+	for n := maxSessionBackups - 1; n >= 1; n-- {
+		if _, err := os.Stat(s.backupPath(n)); err == nil {
+			if err := os.Rename(s.backupPath(n), s.backupPath(n+1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return utils.WriteFileAtomic(s.backupPath(1), current, 0644)
+}
+
+// loadNewestBackup returns the newest backup that can still be read and
+// parsed, along with the path it came from.
+func (s *SessionStore) loadNewestBackup() (*models.SessionFile, string, error) {
+	for n := 1; n <= maxSessionBackups; n++ {
+		path := s.backupPath(n)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		file, _, err := parseSessionFile(data)
+		if err != nil {
+			fmt.Printf("⚠️ Skipping unreadable backup %s: %v\n", path, err)
+			continue
+		}
+		return file, path, nil
+	}
+
+	return nil, "", fmt.Errorf("no valid session backup found")
+}
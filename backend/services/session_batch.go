@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// errBatchFailed aborts a batch update without saving when any account fails.
+var errBatchFailed = errors.New("batch failed")
+
+// BatchResult is the outcome of a batch operation for one account.
+type BatchResult struct {
+	UserID  string `json:"userId"`
+	Changed bool   `json:"changed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchReport is returned by every batch operation. Batches are
+// all-or-nothing: if any account fails, Applied is false, nothing is saved
+// and the failing accounts carry an Error.
+type BatchReport struct {
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// BatchDelete moves every listed session to the recycle bin.
+func (s *SessionStore) BatchDelete(userIDs []string) (*BatchReport, error) {
+	deletedAt := time.Now().Format(time.RFC3339)
+	return s.batchUpdate(mutation{"batch-delete", sourceSessionStore}, userIDs,
+		func(sess *models.LoginSession) error { return nil },
+		func(file *models.SessionFile, userIDs []string) {
+			kept := []models.LoginSession{}
+			for _, sess := range file.Sessions {
+				if !containsString(userIDs, sess.UserID) {
+					kept = append(kept, sess)
+					continue
+				}
+				file.Trash = removeDeleted(file.Trash, sess.UserID)
+				file.Trash = append(file.Trash, models.DeletedSession{LoginSession: sess, DeletedAt: deletedAt})
+			}
+			file.Sessions = kept
+		})
+}
+
+// BatchAddTag adds tag to every listed session.
+func (s *SessionStore) BatchAddTag(userIDs []string, tag string) (*BatchReport, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.batchUpdate(mutation{"batch-add-tag", sourceSessionStore}, userIDs, func(sess *models.LoginSession) error {
+		if hasTag(sess.Tags, tag) {
+			return errNoChanges
+		}
+		sess.Tags = append(sess.Tags, tag)
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	}, nil)
+}
+
+// BatchRemoveTag removes tag from every listed session.
+func (s *SessionStore) BatchRemoveTag(userIDs []string, tag string) (*BatchReport, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.batchUpdate(mutation{"batch-remove-tag", sourceSessionStore}, userIDs, func(sess *models.LoginSession) error {
+		if !hasTag(sess.Tags, tag) {
+			return errNoChanges
+		}
+		sess.Tags = withoutTag(sess.Tags, tag)
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	}, nil)
+}
+
+// BatchResetAliases clears the alias of every listed session, so they show
+// their username again.
+func (s *SessionStore) BatchResetAliases(userIDs []string) (*BatchReport, error) {
+	return s.batchUpdate(mutation{"batch-reset-alias", sourceSessionStore}, userIDs, func(sess *models.LoginSession) error {
+		if sess.Alias == "" {
+			return errNoChanges
+		}
+		sess.Alias = ""
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	}, nil)
+}
+
+// batchSetAvatarImage backs AvatarService.BatchSetAvatar and BatchRemoveAvatar.
+func (s *SessionStore) batchSetAvatarImage(source string, userIDs []string, avatarImage string) (*BatchReport, error) {
+	return s.batchUpdate(mutation{"batch-update-avatar-image", source}, userIDs, func(sess *models.LoginSession) error {
+		if sess.AvatarImage == avatarImage {
+			return errNoChanges
+		}
+		sess.AvatarImage = avatarImage
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	}, nil)
+}
+
+// batchSetAvatarColor backs AvatarService.BatchSetAvatarColor.
+func (s *SessionStore) batchSetAvatarColor(source string, userIDs []string, avatarColor string) (*BatchReport, error) {
+	return s.batchUpdate(mutation{"batch-update-avatar-color", source}, userIDs, func(sess *models.LoginSession) error {
+		if sess.AvatarColor == avatarColor {
+			return errNoChanges
+		}
+		sess.AvatarColor = avatarColor
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	}, nil)
+}
+
+// batchUpdate applies fn to each listed session in a single update. fn
+// returns errNoChanges for a session it left as is. finish, if set, runs
+// once afterwards with the IDs that succeeded, for changes that work on the
+// whole file. Any failure, including an unknown ID, saves nothing.
+func (s *SessionStore) batchUpdate(m mutation, userIDs []string, fn func(sess *models.LoginSession) error, finish func(file *models.SessionFile, userIDs []string)) (*BatchReport, error) {
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("no accounts selected")
+	}
+
+	report := &BatchReport{Results: []BatchResult{}}
+	err := s.update(m, func(file *models.SessionFile) error {
+		report.Results = report.Results[:0]
+		failed := false
+		changed := false
+		seen := map[string]bool{}
+		applied := []string{}
+
+		for _, userID := range userIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+
+			result := BatchResult{UserID: userID}
+			sess := findSession(file.Sessions, userID)
+			err := errors.New("session not found")
+			if sess != nil {
+				err = fn(sess)
+			}
+			switch {
+			case err == nil:
+				result.Changed = true
+				applied = append(applied, userID)
+			case errors.Is(err, errNoChanges):
+				applied = append(applied, userID)
+			default:
+				result.Error = err.Error()
+				failed = true
+			}
+			changed = changed || result.Changed
+			report.Results = append(report.Results, result)
+		}
+
+		if failed {
+			return errBatchFailed
+		}
+		if finish != nil {
+			finish(file, applied)
+			changed = true
+		}
+		if !changed {
+			return errNoChanges
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBatchFailed) {
+		// Nothing was saved, so nothing changed for anyone
+		for i := range report.Results {
+			report.Results[i].Changed = false
+		}
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Applied = true
+	fmt.Printf("📚 Batch %s applied to %d account(s)\n", m.op, len(report.Results))
+	return report, nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// duplicateCreationWindow is how close together two sessions must have been
+// added for their creation times to back up another duplicate signal.
+const duplicateCreationWindow = 2 * time.Minute
+
+// Reasons reported for a DuplicateGroup.
+const (
+	DuplicateByUsername  = "username"
+	DuplicateByToken     = "token"
+	DuplicateByCreatedAt = "createdAt"
+)
+
+// DuplicateGroup is a set of sessions that likely belong to the same real
+// account. Confidence is "high" when they share a token or more than one
+// signal matches, "medium" otherwise.
+type DuplicateGroup struct {
+	UserIDs           []string `json:"userIds"`
+	Reasons           []string `json:"reasons"`
+	Confidence        string   `json:"confidence"`
+	SuggestedSurvivor string   `json:"suggestedSurvivor"`
+}
+
+// FindDuplicateSessions groups sessions that share a username or token.
+// Having been added within moments of each other raises the confidence but
+// never links sessions on its own. Sessions are compared pairwise and linked
+// groups are joined, so A~B and B~C give one group.
+func (s *SessionStore) FindDuplicateSessions() ([]DuplicateGroup, error) {
+	sessions, err := s.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Union-find over session indexes
+	parent := make([]int, len(sessions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := map[int]map[string]bool{}
+	for i := range sessions {
+		for j := i + 1; j < len(sessions); j++ {
+			matched := duplicateSignals(sessions[i], sessions[j])
+			if len(matched) == 0 {
+				continue
+			}
+			ri, rj := root(i), root(j)
+			parent[rj] = ri
+
+			merged := map[string]bool{}
+			for _, r := range []int{ri, rj} {
+				for reason := range reasons[r] {
+					merged[reason] = true
+				}
+				delete(reasons, r)
+			}
+			for _, reason := range matched {
+				merged[reason] = true
+			}
+			reasons[ri] = merged
+		}
+	}
+
+	members := map[int][]models.LoginSession{}
+	for i := range sessions {
+		r := root(i)
+		members[r] = append(members[r], sessions[i])
+	}
+
+	groups := []DuplicateGroup{}
+	for r, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+
+		g := DuplicateGroup{UserIDs: []string{}, Reasons: []string{}, Confidence: "medium"}
+		for _, sess := range group {
+			g.UserIDs = append(g.UserIDs, sess.UserID)
+		}
+		for reason := range reasons[r] {
+			g.Reasons = append(g.Reasons, reason)
+		}
+		sort.Strings(g.Reasons)
+		if reasons[r][DuplicateByToken] || len(g.Reasons) > 1 {
+			g.Confidence = "high"
+		}
+		g.SuggestedSurvivor = suggestSurvivor(group).UserID
+		groups = append(groups, g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].UserIDs[0] < groups[j].UserIDs[0] })
+	return groups, nil
+}
+
+// MergeSessions folds the duplicates into survivorID and moves them to the
+// recycle bin. The survivor keeps its own alias, username, avatar and color
+// where set and takes the duplicates' otherwise; tags are combined, usage
+// stats added up, and the most recently updated token is kept.
+func (s *SessionStore) MergeSessions(survivorID string, duplicateIDs []string) (*models.LoginSession, error) {
+	var merged models.LoginSession
+	count := 0
+	err := s.update(mutation{"merge", sourceSessionStore}, func(file *models.SessionFile) error {
+		survivor := findSession(file.Sessions, survivorID)
+		if survivor == nil {
+			return fmt.Errorf("session not found")
+		}
+
+		duplicates := []models.LoginSession{}
+		seen := map[string]bool{survivorID: true}
+		for _, id := range duplicateIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			dup := findSession(file.Sessions, id)
+			if dup == nil {
+				return fmt.Errorf("session %s not found", id)
+			}
+			duplicates = append(duplicates, *dup)
+		}
+		if len(duplicates) == 0 {
+			return fmt.Errorf("nothing to merge")
+		}
+		count = len(duplicates)
+
+		tokenFrom := *survivor
+		for _, dup := range duplicates {
+			survivor.Alias = firstNonEmpty(survivor.Alias, dup.Alias)
+			survivor.Username = firstNonEmpty(survivor.Username, dup.Username)
+			survivor.AvatarImage = firstNonEmpty(survivor.AvatarImage, dup.AvatarImage)
+			survivor.AvatarColor = firstNonEmpty(survivor.AvatarColor, dup.AvatarColor)
+			for _, tag := range dup.Tags {
+				if !hasTag(survivor.Tags, tag) {
+					survivor.Tags = append(survivor.Tags, tag)
+				}
+			}
+			survivor.Pinned = survivor.Pinned || dup.Pinned
+			survivor.SwitchCount += dup.SwitchCount
+			if lastUsed(dup).After(lastUsed(*survivor)) {
+				survivor.LastUsedAt = dup.LastUsedAt
+			}
+			if created, err := time.Parse(time.RFC3339, dup.CreatedAt); err == nil {
+				if current, err := time.Parse(time.RFC3339, survivor.CreatedAt); err != nil || created.Before(current) {
+					survivor.CreatedAt = dup.CreatedAt
+				}
+			}
+			if dup.LoginToken != "" && (tokenFrom.LoginToken == "" || isNewer(dup, tokenFrom)) {
+				tokenFrom = dup
+			}
+		}
+		survivor.LoginToken = tokenFrom.LoginToken
+		survivor.TokenFingerprint = tokenFrom.TokenFingerprint
+		survivor.NeedsRelogin = tokenFrom.NeedsRelogin
+		survivor.UpdatedAt = time.Now().Format(time.RFC3339)
+		merged = cloneSession(*survivor)
+
+		// Duplicates go to the recycle bin, so a wrong merge can be undone
+		deletedAt := time.Now().Format(time.RFC3339)
+		kept := []models.LoginSession{}
+		for _, sess := range file.Sessions {
+			if sess.UserID != survivorID && containsString(duplicateIDs, sess.UserID) {
+				file.Trash = removeDeleted(file.Trash, sess.UserID)
+				file.Trash = append(file.Trash, models.DeletedSession{LoginSession: sess, DeletedAt: deletedAt})
+				continue
+			}
+			kept = append(kept, sess)
+		}
+		file.Sessions = kept
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🔗 Merged %d duplicate(s) into %s\n", count, survivorID)
+	return &merged, nil
+}
+
+// duplicateSignals returns the reasons a and b look like the same account,
+// or none if they share neither a username nor a token. A close creation
+// time only adds to those: accounts added back to back are usually
+// different ones.
+func duplicateSignals(a, b models.LoginSession) []string {
+	reasons := []string{}
+	if a.Username != "" && strings.EqualFold(a.Username, b.Username) {
+		reasons = append(reasons, DuplicateByUsername)
+	}
+	if a.TokenFingerprint != "" && a.TokenFingerprint == b.TokenFingerprint {
+		reasons = append(reasons, DuplicateByToken)
+	}
+	if len(reasons) == 0 {
+		return reasons
+	}
+	ca, errA := time.Parse(time.RFC3339, a.CreatedAt)
+	cb, errB := time.Parse(time.RFC3339, b.CreatedAt)
+	if errA == nil && errB == nil {
+		if diff := ca.Sub(cb); diff < duplicateCreationWindow && diff > -duplicateCreationWindow {
+			reasons = append(reasons, DuplicateByCreatedAt)
+		}
+	}
+	return reasons
+}
+
+// suggestSurvivor picks the entry to keep: the one with a username, then
+// the most recently used, then the most recently updated.
+func suggestSurvivor(group []models.LoginSession) models.LoginSession {
+	best := group[0]
+	for _, sess := range group[1:] {
+		switch {
+		case (sess.Username != "") != (best.Username != ""):
+			if sess.Username != "" {
+				best = sess
+			}
+		case !lastUsed(sess).Equal(lastUsed(best)):
+			if lastUsed(sess).After(lastUsed(best)) {
+				best = sess
+			}
+		case isNewer(sess, best):
+			best = sess
+		}
+	}
+	return best
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
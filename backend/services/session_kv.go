@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// kvFileName is the embedded database used by vaults migrated to StorageKV.
+const kvFileName = "login_sessions.db"
+
+var (
+	kvSessionsBucket = []byte("sessions")
+	kvTrashBucket    = []byte("trash")
+	kvMetaBucket     = []byte("meta")
+	kvSchemaKey      = []byte("schemaVersion")
+	kvAppVersionKey  = []byte("appVersion")
+)
+
+// kvBackend keeps one record per session in an embedded bbolt database, so
+// an edit rewrites only the records that changed, inside one transaction.
+// The database is opened per operation, which keeps it readable by a
+// second app window between writes.
+type kvBackend struct {
+	file string
+}
+
+// kvPathFor returns the database path for the vault whose JSON sessions
+// file is sessionPath.
+func kvPathFor(sessionPath string) string {
+	return filepath.Join(filepath.Dir(sessionPath), kvFileName)
+}
+
+func (b *kvBackend) name() string { return StorageKV }
+
+func (b *kvBackend) path() string { return b.file }
+
+func (b *kvBackend) open(readOnly bool) (*bolt.DB, error) {
+	if readOnly && !fileExists(b.file) {
+		return nil, fmt.Errorf("sessions database not found: %s", b.file)
+	}
+	db, err := bolt.Open(b.file, 0600, &bolt.Options{Timeout: 2 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sessions database: %w", err)
+	}
+	return db, nil
+}
+
+func (b *kvBackend) load() (*models.SessionFile, error) {
+	db, err := b.open(true)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	file := &models.SessionFile{Sessions: []models.LoginSession{}}
+	err = db.View(func(tx *bolt.Tx) error {
+		if meta := tx.Bucket(kvMetaBucket); meta != nil {
+			file.SchemaVersion, _ = strconv.Atoi(string(meta.Get(kvSchemaKey)))
+			file.AppVersion = string(meta.Get(kvAppVersionKey))
+		}
+		if file.SchemaVersion > currentSchemaVersion {
+			return newerSchemaError(file.SchemaVersion)
+		}
+
+		if bucket := tx.Bucket(kvSessionsBucket); bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				var sess models.LoginSession
+				if err := json.Unmarshal(v, &sess); err != nil {
+					return fmt.Errorf("damaged record %s: %w", k, err)
+				}
+				file.Sessions = append(file.Sessions, sess)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if bucket := tx.Bucket(kvTrashBucket); bucket != nil {
+			return bucket.ForEach(func(k, v []byte) error {
+				var deleted models.DeletedSession
+				if err := json.Unmarshal(v, &deleted); err != nil {
+					return fmt.Errorf("damaged record %s: %w", k, err)
+				}
+				file.Trash = append(file.Trash, deleted)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortSessions(file.Sessions)
+	return file, nil
+}
+
+// save writes only the records that differ from what's stored and deletes
+// the ones no longer present, all in one transaction.
+func (b *kvBackend) save(file *models.SessionFile) error {
+	db, err := b.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		sessions := map[string][]byte{}
+		for _, sess := range file.Sessions {
+			data, err := json.Marshal(sess)
+			if err != nil {
+				return err
+			}
+			sessions[sess.UserID] = data
+		}
+		if err := syncBucket(tx, kvSessionsBucket, sessions); err != nil {
+			return err
+		}
+
+		trash := map[string][]byte{}
+		for _, deleted := range file.Trash {
+			data, err := json.Marshal(deleted)
+			if err != nil {
+				return err
+			}
+			trash[deleted.UserID] = data
+		}
+		if err := syncBucket(tx, kvTrashBucket, trash); err != nil {
+			return err
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(kvMetaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put(kvSchemaKey, []byte(strconv.Itoa(file.SchemaVersion))); err != nil {
+			return err
+		}
+		return meta.Put(kvAppVersionKey, []byte(file.AppVersion))
+	})
+}
+
+// rewriteTokens applies fn to every stored token in one transaction.
+func (b *kvBackend) rewriteTokens(fn func(token string) (string, error)) error {
+	db, err := b.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{kvSessionsBucket, kvTrashBucket} {
+			bucket := tx.Bucket(name)
+			if bucket == nil {
+				continue
+			}
+
+			updates := map[string][]byte{}
+			err := bucket.ForEach(func(k, v []byte) error {
+				var record map[string]any
+				if err := json.Unmarshal(v, &record); err != nil {
+					return err
+				}
+				changed, err := walkTokenValues(record, fn)
+				if err != nil || !changed {
+					return err
+				}
+				data, err := json.Marshal(record)
+				if err != nil {
+					return err
+				}
+				updates[string(k)] = data
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			// Keys can't be written while iterating
+			for k, v := range updates {
+				if err := bucket.Put([]byte(k), v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// syncBucket makes bucket hold exactly records, touching only keys whose
+// value differs.
+func syncBucket(tx *bolt.Tx, name []byte, records map[string][]byte) error {
+	bucket, err := tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return err
+	}
+
+	stale := [][]byte{}
+	err = bucket.ForEach(func(k, _ []byte) error {
+		if _, ok := records[string(k)]; !ok {
+			stale = append(stale, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range stale {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range records {
+		if bytes.Equal(bucket.Get([]byte(k)), v) {
+			continue
+		}
+		if err := bucket.Put([]byte(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteVaultTokens applies fn to every token stored by the vault whose
+// sessions file is sessionPath, in whichever backend it uses. It reports
+// false if the vault has no stored sessions.
+func rewriteVaultTokens(sessionPath string, fn func(token string) (string, error)) (bool, error) {
+	if kvPath := kvPathFor(sessionPath); fileExists(kvPath) {
+		return true, (&kvBackend{file: kvPath}).rewriteTokens(fn)
+	}
+	if !fileExists(sessionPath) {
+		return false, nil
+	}
+	return true, rewriteTokenValues(sessionPath, fn)
+}
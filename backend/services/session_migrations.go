@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// currentSchemaVersion is the sessions file schema written by this build.
+const currentSchemaVersion = 2
+
+// sessionMigration upgrades a raw sessions file from one schema version to
+// the next. Migrations work on raw JSON since older layouts don't match
+// today's models.
+type sessionMigration struct {
+	from    int
+	name    string
+	migrate func(data []byte) ([]byte, error)
+}
+
+// sessionMigrations is the registered chain, applied in order. Each entry
+// upgrades from its version to from+1; add new ones at the end and bump
+// currentSchemaVersion.
+var sessionMigrations = []sessionMigration{
+	{from: 0, name: "wrap legacy session array in a versioned envelope", migrate: migrateLegacyArray},
+	{from: 1, name: "record explicit sort order", migrate: migrateSortOrder},
+}
+
+// migrateLegacyArray upgrades a pre-versioning file (a bare JSON array of
+// sessions) to the versioned envelope.
+func migrateLegacyArray(data []byte) ([]byte, error) {
+	var sessions []json.RawMessage
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	if sessions == nil {
+		sessions = []json.RawMessage{}
+	}
+
+	return json.Marshal(map[string]any{
+		"schemaVersion": 1,
+		"appVersion":    utils.AppVersion,
+		"sessions":      sessions,
+	})
+}
+
+// migrateSortOrder gives every session an explicit sortOrder matching its
+// position in the file, which is the order it was listed in until now.
+func migrateSortOrder(data []byte) ([]byte, error) {
+	var file map[string]any
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	if sessions, ok := file["sessions"].([]any); ok {
+		for i, raw := range sessions {
+			if sess, ok := raw.(map[string]any); ok {
+				sess["sortOrder"] = i
+			}
+		}
+	}
+	file["schemaVersion"] = 2
+
+	return json.Marshal(file)
+}
+
+// detectSchemaVersion reports the schema version of a raw sessions file.
+func detectSchemaVersion(data []byte) (int, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return 0, nil
+	}
+
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(trimmed, &header); err != nil {
+		return 0, err
+	}
+	return header.SchemaVersion, nil
+}
+
+// ErrNewerSchema is returned when the sessions were saved by a newer release
+// of the app. They are opened read-only rather than treated as corrupt, so
+// going back to an older build never replaces them with a backup.
+var ErrNewerSchema = errors.New("sessions were saved by a newer version of the app and are read-only")
+
+func newerSchemaError(version int) error {
+	return fmt.Errorf("%w (schema v%d, this build supports v%d)", ErrNewerSchema, version, currentSchemaVersion)
+}
+
+// parseSessionFile decodes the raw contents of a sessions file, running any
+// migrations needed to reach currentSchemaVersion. It returns the schema
+// version the data was originally in.
+func parseSessionFile(data []byte) (*models.SessionFile, int, error) {
+	version, err := detectSchemaVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > currentSchemaVersion {
+		return nil, version, newerSchemaError(version)
+	}
+
+	original := version
+	for version < currentSchemaVersion {
+		migration, ok := findSessionMigration(version)
+		if !ok {
+			return nil, original, fmt.Errorf("no migration registered from schema v%d", version)
+		}
+		if data, err = migration.migrate(data); err != nil {
+			return nil, original, fmt.Errorf("migration from v%d (%s) failed: %w", version, migration.name, err)
+		}
+		version++
+	}
+
+	var file models.SessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, original, err
+	}
+	if file.Sessions == nil {
+		file.Sessions = []models.LoginSession{}
+	}
+	return &file, original, nil
+}
+
+func findSessionMigration(from int) (sessionMigration, bool) {
+	for _, m := range sessionMigrations {
+		if m.from == from {
+			return m, true
+		}
+	}
+	return sessionMigration{}, false
+}
+
+// backupBeforeMigration keeps a copy of the file as it was before being
+// upgraded, named after the schema version it was in.
+func (s *SessionStore) backupBeforeMigration(data []byte, fromVersion int) error {
+	path := fmt.Sprintf("%s.pre-migration-v%d", s.filePath, fromVersion)
+	if _, err := os.Stat(path); err == nil {
+		return nil // Keep the oldest copy if a migration is retried
+	}
+	return utils.WriteFileAtomic(path, data, 0644)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// ReorderSessions persists a drag-and-drop order. userIDs lists sessions in
+// their new order; sessions not listed keep their relative order after them.
+// Pinned sessions still sort ahead of unpinned ones.
+func (s *SessionStore) ReorderSessions(userIDs []string) error {
+	return s.update(mutation{"reorder", sourceSessionStore}, func(file *models.SessionFile) error {
+		position := map[string]int{}
+		for i, userID := range userIDs {
+			if _, seen := position[userID]; !seen {
+				position[userID] = i
+			}
+		}
+
+		// Listed sessions first (in the given order), then the rest as they were
+		sortSessions(file.Sessions)
+		sort.SliceStable(file.Sessions, func(i, j int) bool {
+			pi, iListed := position[file.Sessions[i].UserID]
+			pj, jListed := position[file.Sessions[j].UserID]
+			if iListed != jListed {
+				return iListed
+			}
+			return iListed && pi < pj
+		})
+
+		for i := range file.Sessions {
+			file.Sessions[i].SortOrder = i
+		}
+		return nil
+	})
+}
+
+// SetPinned pins or unpins a session. Pinned sessions always sort first.
+func (s *SessionStore) SetPinned(userID string, pinned bool) error {
+	return s.updateSession(mutation{"set-pinned", sourceSessionStore}, userID, func(sess *models.LoginSession) error {
+		if sess.Pinned == pinned {
+			return errNoChanges
+		}
+		sess.Pinned = pinned
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+// sortSessions puts sessions in display order: pinned first, then by
+// sortOrder. Every listing goes through this, so the order is the same
+// everywhere sessions are shown.
+func sortSessions(sessions []models.LoginSession) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Pinned != sessions[j].Pinned {
+			return sessions[i].Pinned
+		}
+		return sessions[i].SortOrder < sessions[j].SortOrder
+	})
+}
+
+// nextSortOrder returns a sortOrder that places a new session last.
+func nextSortOrder(sessions []models.LoginSession) int {
+	next := 0
+	for _, sess := range sessions {
+		if sess.SortOrder >= next {
+			next = sess.SortOrder + 1
+		}
+	}
+	return next
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// ErrCodeSessionFileCorrupt prefixes the message of a SessionFileCorruptError,
+// so the frontend can recognise it after Wails flattens errors to strings.
+const ErrCodeSessionFileCorrupt = "SESSION_FILE_CORRUPT"
+
+// sessionsArrayStart and trashArrayStart find where the envelope's arrays
+// begin, so a key that only appears as a value (e.g. an alias) isn't taken
+// for them.
+var (
+	sessionsArrayStart = regexp.MustCompile(`"sessions"\s*:\s*\[`)
+	trashArrayStart    = regexp.MustCompile(`"trash"\s*:\s*\[`)
+)
+
+// SessionFileCorruptError is returned when the sessions file can't be parsed
+// and no valid backup exists. Writes are refused while this is the case, so
+// the damaged file is never overwritten with an empty list.
+type SessionFileCorruptError struct {
+	Path           string
+	QuarantinePath string
+	Cause          error
+}
+
+func (e *SessionFileCorruptError) Error() string {
+	msg := fmt.Sprintf("%s: sessions file %s is corrupt: %v", ErrCodeSessionFileCorrupt, e.Path, e.Cause)
+	if e.QuarantinePath != "" {
+		msg += fmt.Sprintf(" (copy saved to %s)", e.QuarantinePath)
+	}
+	return msg
+}
+
+func (e *SessionFileCorruptError) Unwrap() error {
+	return e.Cause
+}
+
+// RepairReport describes the outcome of RepairSessions.
+type RepairReport struct {
+	Recovered      int    `json:"recovered"`
+	TrashRecovered int    `json:"trashRecovered"`
+	Skipped        int    `json:"skipped"`
+	QuarantinePath string `json:"quarantinePath"`
+}
+
+// quarantine saves a timestamped copy of a damaged sessions file next to it.
+// If an identical copy was already quarantined, that path is reused instead
+// of piling up duplicates on every load.
+func (s *SessionStore) quarantine(data []byte) (string, error) {
+	existing, _ := filepath.Glob(s.filePath + ".corrupt-*")
+	for _, path := range existing {
+		if prev, err := os.ReadFile(path); err == nil && bytes.Equal(prev, data) {
+			return path, nil
+		}
+	}
+
+	path := fmt.Sprintf("%s.corrupt-%s", s.filePath, time.Now().Format("20060102-150405"))
+	if err := utils.WriteFileAtomic(path, data, 0644); err != nil {
+		return "", err
+	}
+	fmt.Println("🧯 Quarantined corrupt sessions file to:", path)
+	return path, nil
+}
+
+// latestQuarantine returns the most recent quarantined copy, if any.
+func (s *SessionStore) latestQuarantine() string {
+	existing, _ := filepath.Glob(s.filePath + ".corrupt-*")
+	if len(existing) == 0 {
+		return ""
+	}
+	// Timestamps sort lexically
+	sort.Strings(existing)
+	return existing[len(existing)-1]
+}
+
+// RepairSessions recovers as many sessions and recycle bin entries as
+// possible from a corrupt sessions file and writes them back as a clean
+// file. The damaged original is quarantined first, so nothing is lost if the
+// recovery misses entries.
+func (s *SessionStore) RepairSessions() (*RepairReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureWritable(); err != nil {
+		return nil, err
+	}
+	if s.backend.name() != StorageJSON {
+		return &RepairReport{}, nil // The database keeps itself consistent
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read sessions file: %w", err)
+	}
+
+	// Nothing to repair if the file already parses, and nothing this build
+	// may touch if a newer one wrote it
+	if err == nil {
+		_, _, parseErr := parseSessionFile(data)
+		if parseErr == nil {
+			return &RepairReport{}, nil
+		}
+		if errors.Is(parseErr, ErrNewerSchema) {
+			return nil, parseErr
+		}
+	}
+
+	report := &RepairReport{}
+	if err == nil {
+		if report.QuarantinePath, err = s.quarantine(data); err != nil {
+			return nil, fmt.Errorf("failed to quarantine sessions file: %w", err)
+		}
+	} else {
+		// The main file is gone; repair from the last quarantined copy
+		report.QuarantinePath = s.latestQuarantine()
+		if report.QuarantinePath == "" {
+			return nil, fmt.Errorf("no sessions file to repair")
+		}
+		if data, err = os.ReadFile(report.QuarantinePath); err != nil {
+			return nil, fmt.Errorf("failed to read quarantined file: %w", err)
+		}
+	}
+
+	sessions, skipped := recoverSessions(data)
+	trash, trashSkipped := recoverTrash(data)
+	report.Recovered = len(sessions)
+	report.TrashRecovered = len(trash)
+	report.Skipped = skipped + trashSkipped
+
+	file := &models.SessionFile{Sessions: sessions, Trash: trash}
+	if err := s.saveFile(file); err != nil {
+		return nil, fmt.Errorf("failed to save repaired sessions: %w", err)
+	}
+	s.setCache(file)
+	s.journal(mutation{"repair", sourceSessionStore}, &models.SessionFile{}, file)
+
+	fmt.Printf("🩹 Repaired sessions file: %d recovered, %d in trash, %d skipped\n", report.Recovered, report.TrashRecovered, report.Skipped)
+	return report, nil
+}
+
+// recoverSessions scans partial JSON for session objects and decodes each
+// one independently, so a single damaged entry doesn't take the rest with it.
+// Objects that don't decode or have no user ID are counted as skipped.
+func recoverSessions(data []byte) ([]models.LoginSession, int) {
+	sessions := []models.LoginSession{}
+	skipped := 0
+	seen := map[string]bool{}
+
+	// Versioned files wrap the array in an envelope; skip straight to it
+	if loc := sessionsArrayStart.FindIndex(data); loc != nil {
+		data = data[loc[1]:]
+	} else if open := bytes.IndexByte(data, '['); open != -1 {
+		data = data[open+1:]
+	}
+
+	for _, raw := range scanObjects(data) {
+		var sess models.LoginSession
+		if err := json.Unmarshal(raw, &sess); err != nil || sess.UserID == "" || seen[sess.UserID] {
+			skipped++
+			continue
+		}
+		seen[sess.UserID] = true
+		sessions = append(sessions, sess)
+	}
+
+	return sessions, skipped
+}
+
+// recoverTrash does the same for the recycle bin of a versioned file. An
+// account can be both live and in the bin, when it was added again after
+// being deleted, so entries are only deduplicated within the bin.
+func recoverTrash(data []byte) ([]models.DeletedSession, int) {
+	loc := trashArrayStart.FindIndex(data)
+	if loc == nil {
+		return nil, 0
+	}
+
+	seen := map[string]bool{}
+	trash := []models.DeletedSession{}
+	skipped := 0
+	for _, raw := range scanObjects(data[loc[1]:]) {
+		var deleted models.DeletedSession
+		if err := json.Unmarshal(raw, &deleted); err != nil || deleted.UserID == "" || seen[deleted.UserID] {
+			skipped++
+			continue
+		}
+		seen[deleted.UserID] = true
+		trash = append(trash, deleted)
+	}
+
+	return trash, skipped
+}
+
+// scanObjects returns every balanced {...} object found at the shallowest
+// object depth of data, honouring string literals and escapes, and stops at
+// the ] closing the enclosing array. An object left unterminated by
+// truncation is returned as-is so it counts as skipped.
+func scanObjects(data []byte) [][]byte {
+	var objects [][]byte
+	depth := 0
+	start := -1
+	inString := false
+	escaped := false
+
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ']':
+			if depth == 0 {
+				return objects
+			}
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				objects = append(objects, data[start:i+1])
+				start = -1
+			}
+		}
+	}
+
+	if start != -1 {
+		objects = append(objects, data[start:])
+	}
+
+	return objects
+}
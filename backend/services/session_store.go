@@ -1,162 +1,613 @@
 package services
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
 	"epic-games-account-switcher/backend/utils"
 )
 
+// ErrSessionStoreReadOnly is returned by writes while another running copy of
+// the app holds the sessions file lock.
+var ErrSessionStoreReadOnly = errors.New("sessions are read-only while another Epic Switcher window is open")
+
+// errNoChanges lets an update callback skip the save when nothing changed.
+var errNoChanges = errors.New("no changes")
+
+// SessionStore is the single owner of the active vault's sessions. One
+// instance is shared by every service, so all read-modify-write cycles go
+// through its mutex and in-memory cache. The data lives in a sessionBackend,
+// login_sessions.json unless the vault was migrated to the key-value store.
+// A lock file keeps other processes out; if it's already held, the store
+// falls back to read-only. Login tokens are encrypted on disk with protector
+// and only decrypted by revealLoginToken. While the app lock is engaged,
+// sessions can't be read or written at all.
 type SessionStore struct {
 	filePath string
+	settings *SettingsService
+
+	mu        sync.Mutex
+	ctx       context.Context
+	backend   sessionBackend
+	protector security.Protector
+	locked    bool
+	cache     *models.SessionFile
+	cacheStat os.FileInfo
+	cacheSum  [sha256.Size]byte
+	// cacheAt is when the cached contents were last known to match the
+	// file on disk.
+	cacheAt  time.Time
+	lock     *utils.FileLock
+	readOnly bool
+	// newerSchema is set while the loaded sessions come from a newer
+	// release; writes are refused with ErrNewerSchema.
+	newerSchema bool
+}
+
+func NewSessionStore(settings *SettingsService) *SessionStore {
+	return NewSessionStoreWithProtector(settings, defaultProtector(utils.GetAppDataPath()))
 }
 
-func NewSessionStore() *SessionStore {
-	path := filepath.Join(utils.GetAppDataPath(), "login_sessions.json")
-	return &SessionStore{filePath: path}
+// NewSessionStoreWithProtector creates a store that encrypts tokens with the
+// given protector instead of the platform default (e.g. a NoopProtector in tests).
+func NewSessionStoreWithProtector(settings *SettingsService, protector security.Protector) *SessionStore {
+	s := &SessionStore{filePath: vaultSessionPath(defaultVaultID), settings: settings, protector: protector}
+	s.backend = s.backendFor(s.filePath)
+	s.acquireLock()
+	return s
 }
 
 func (s *SessionStore) GetAvatarDir() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return filepath.Join(filepath.Dir(s.filePath), "avatars")
 }
 
-func (s *SessionStore) LoadSessions() ([]models.LoginSession, error) {
-	s.ensureFile()
-	data, err := os.ReadFile(s.filePath)
+// open points the store at another sessions file, i.e. another vault. The
+// lock on the current file is released and the new one is locked instead.
+func (s *SessionStore) open(filePath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lock != nil {
+		s.lock.Unlock()
+		s.lock = nil
+	}
+	s.filePath = filePath
+	s.backend = s.backendFor(filePath)
+	s.cache = nil
+	s.cacheStat = nil
+	s.cacheSum = [sha256.Size]byte{}
+	s.cacheAt = time.Time{}
+	s.readOnly = false
+	s.newerSchema = false
+	s.acquireLock()
+}
+
+// IsReadOnly reports whether another running copy of the app owns the store,
+// or the sessions were saved by a newer release.
+func (s *SessionStore) IsReadOnly() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readOnly || s.newerSchema
+}
+
+// acquireLock takes the cross-process lock on the sessions file, switching
+// the store to read-only if another process already holds it.
+func (s *SessionStore) acquireLock() {
+	if err := s.ensureDir(); err != nil {
+		fmt.Printf("⚠️ Failed to create app data directory: %v\n", err)
+		return
+	}
+
+	lock, err := utils.TryLockFile(s.filePath + ".lock")
+	switch {
+	case errors.Is(err, utils.ErrFileLocked):
+		if !s.readOnly {
+			fmt.Println("🔒 Sessions file is in use by another instance, opening read-only.")
+		}
+		s.readOnly = true
+	case err != nil:
+		// Couldn't create the lock file at all; carry on unlocked rather
+		// than blocking the app.
+		fmt.Printf("⚠️ Failed to lock sessions file: %v\n", err)
+	default:
+		if s.readOnly {
+			fmt.Println("🔓 Sessions file lock acquired, store is writable again.")
+		}
+		s.lock = lock
+		s.readOnly = false
+	}
+}
+
+// ensureWritable retries the lock when read-only, so the store recovers once
+// the other instance exits.
+func (s *SessionStore) ensureWritable() error {
+	if s.newerSchema {
+		return ErrNewerSchema
+	}
+	if s.readOnly {
+		s.acquireLock()
+	}
+	if s.readOnly {
+		return ErrSessionStoreReadOnly
+	}
+	return nil
+}
+
+// current returns the cached sessions file, reloading it when the file on
+// disk has changed since it was cached (and telling the frontend what
+// changed). Callers must hold s.mu and must not modify the result; use
+// update for writes.
+func (s *SessionStore) current() (*models.SessionFile, error) {
+	if s.locked {
+		return nil, ErrAppLocked
+	}
+
+	path := s.backend.path()
+	info, statErr := os.Stat(path)
+	if s.cache != nil && statErr == nil && sameFileState(s.cacheStat, info) {
+		if !mtimeUnsettled(info, s.cacheAt) {
+			return s.cache, nil
+		}
+		// An edit within the filesystem's timestamp resolution that keeps
+		// the size looks unchanged, so confirm by content
+		if sum, err := fileSum(path); err == nil && sum == s.cacheSum {
+			s.cacheAt = time.Now()
+			return s.cache, nil
+		}
+	}
+
+	s.newerSchema = false
+	file, err := s.backend.load()
 	if err != nil {
+		s.newerSchema = errors.Is(err, ErrNewerSchema)
+		s.cache = nil
 		return nil, err
 	}
-	var sessions []models.LoginSession
-	_ = json.Unmarshal(data, &sessions)
-	return sessions, nil
+	s.notifyReload(s.cache, file)
+	s.setCache(file)
+	return file, nil
 }
 
-func (s *SessionStore) SaveSessions(sessions []models.LoginSession) error {
-	data, err := json.MarshalIndent(sessions, "", "  ")
+// setCache remembers file as the current contents of the sessions file.
+func (s *SessionStore) setCache(file *models.SessionFile) {
+	path := s.backend.path()
+	s.cache = file
+	s.cacheStat, _ = os.Stat(path)
+	s.cacheAt = time.Now()
+	s.cacheSum = [sha256.Size]byte{}
+	if s.cacheStat != nil && mtimeUnsettled(s.cacheStat, s.cacheAt) {
+		s.cacheSum, _ = fileSum(path)
+	}
+}
+
+// mtimeGranularity is the coarsest file timestamp resolution expected (FAT
+// keeps two seconds).
+const mtimeGranularity = 2 * time.Second
+
+// mtimeUnsettled reports whether a write after seenAt could leave info's
+// modification time unchanged. Only then does the content need hashing: once
+// a file is older than the timestamp resolution, any later write moves it.
+func mtimeUnsettled(info os.FileInfo, seenAt time.Time) bool {
+	return seenAt.Sub(info.ModTime()) <= mtimeGranularity
+}
+
+// sameFileState reports whether two stats may describe the same file
+// contents.
+func sameFileState(a, b os.FileInfo) bool {
+	return a != nil && b != nil && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+// fileSum returns the SHA-256 of the file at path.
+func fileSum(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return [sha256.Size]byte{}, err
 	}
-	return os.WriteFile(s.filePath, data, 0644)
+	return sha256.Sum256(data), nil
 }
 
-func (s *SessionStore) DeleteSession(userID string) error {
-	sessions, err := s.LoadSessions()
+// update runs fn on a copy of the current sessions file and saves the result,
+// all under the store mutex. Returning errNoChanges from fn skips the save.
+// Expired recycle bin entries are purged along with any save, and sessions
+// are kept in display order on disk. Saved changes are journaled as m.
+func (s *SessionStore) update(m mutation, fn func(file *models.SessionFile) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureWritable(); err != nil {
+		return err
+	}
+
+	file, err := s.current()
 	if err != nil {
 		return err
 	}
+	if s.newerSchema {
+		return ErrNewerSchema
+	}
 
-	updated := []models.LoginSession{}
-	for _, sess := range sessions {
-		if sess.UserID != userID {
-			updated = append(updated, sess)
+	next := cloneSessionFile(file)
+	if err := fn(next); err != nil {
+		if errors.Is(err, errNoChanges) {
+			return nil
 		}
+		return err
 	}
+	edited := cloneSessionFile(next)
+	purgeExpiredTrash(next, s.settings.trashRetention())
+	sortSessions(next.Sessions)
 
-	return s.SaveSessions(updated)
+	if err := s.saveFile(next); err != nil {
+		return err
+	}
+	s.setCache(next)
+
+	s.journal(m, file, edited)
+	s.journal(mutation{"purge-expired", sourceSessionStore}, edited, next)
+	return nil
 }
 
-func (s *SessionStore) UpdateAlias(userID string, alias string) error {
-	sessions, _ := s.LoadSessions()
+// updateSession applies fn to the session with the given userID.
+func (s *SessionStore) updateSession(m mutation, userID string, fn func(sess *models.LoginSession) error) error {
+	return s.update(m, func(file *models.SessionFile) error {
+		sess := findSession(file.Sessions, userID)
+		if sess == nil {
+			return fmt.Errorf("session not found")
+		}
+		return fn(sess)
+	})
+}
 
+// findSession returns a pointer into sessions for the given userID, or nil.
+func findSession(sessions []models.LoginSession, userID string) *models.LoginSession {
 	for i := range sessions {
 		if sessions[i].UserID == userID {
-			sessions[i].Alias = alias
-			sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-			return s.SaveSessions(sessions)
+			return &sessions[i]
 		}
 	}
+	return nil
+}
+
+// cloneSessionFile deep-copies file so callers can modify it freely.
+func cloneSessionFile(file *models.SessionFile) *models.SessionFile {
+	clone := *file
+	clone.Sessions = cloneSessions(file.Sessions)
+	clone.Trash = make([]models.DeletedSession, len(file.Trash))
+	for i, deleted := range file.Trash {
+		deleted.LoginSession = cloneSession(deleted.LoginSession)
+		clone.Trash[i] = deleted
+	}
+	return &clone
+}
 
-	return fmt.Errorf("session not found")
+func cloneSessions(sessions []models.LoginSession) []models.LoginSession {
+	clone := make([]models.LoginSession, len(sessions))
+	for i, sess := range sessions {
+		clone[i] = cloneSession(sess)
+	}
+	return clone
 }
 
-func (s *SessionStore) UpdateAvatarImage(userID string, avatarImage string) error {
-	sessions, _ := s.LoadSessions()
+// cloneSession copies sess, including its slices.
+func cloneSession(sess models.LoginSession) models.LoginSession {
+	sess.Tags = append([]string(nil), sess.Tags...)
+	return sess
+}
 
-	for i := range sessions {
-		if sessions[i].UserID == userID {
-			sessions[i].AvatarImage = avatarImage
-			sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-			return s.SaveSessions(sessions)
+func (s *SessionStore) LoadSessions() ([]models.LoginSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := cloneSessions(file.Sessions)
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+// loadFile reads the full sessions file from disk, upgrading it to the
+// current schema if needed. The pre-migration file is kept as a backup before
+// the upgraded version is written back. Callers must hold s.mu.
+func (s *SessionStore) loadFile() (*models.SessionFile, error) {
+	s.ensureFile()
+	data, err := os.ReadFile(s.filePath)
+	if err == nil {
+		file, fromVersion, parseErr := parseSessionFile(data)
+		if errors.Is(parseErr, ErrNewerSchema) {
+			return s.openNewerSchema(data, parseErr)
+		}
+		if parseErr != nil {
+			return s.recoverFromCorruption(data, parseErr)
 		}
+		hadPlaintextTokens := hasPlaintextTokens(file)
+		if fromVersion < currentSchemaVersion {
+			s.migrateFile(data, fromVersion, file)
+		}
+		if hadPlaintextTokens {
+			s.encryptPlaintextTokens(file)
+		}
+		return file, nil
 	}
 
-	return fmt.Errorf("session not found")
+	// Main file is unreadable → fall back to the newest valid backup
+	fmt.Printf("⚠️ Failed to read sessions file: %v\n", err)
+	file, backupPath, backupErr := s.loadNewestBackup()
+	if backupErr != nil {
+		return nil, fmt.Errorf("failed to read sessions file: %w", err)
+	}
+	fmt.Println("🛟 Loaded sessions from backup:", backupPath)
+	return file, nil
 }
 
-func (s *SessionStore) UpdateAvatarColor(userID string, avatarColor string) error {
-	sessions, _ := s.LoadSessions()
+// openNewerSchema reads a sessions file from a newer release as far as this
+// build understands it and marks the store read-only, so the file is never
+// overwritten or swapped for an older backup. Callers must hold s.mu.
+func (s *SessionStore) openNewerSchema(data []byte, cause error) (*models.SessionFile, error) {
+	fmt.Printf("⚠️ %v\n", cause)
+	s.newerSchema = true
 
-	for i := range sessions {
-		if sessions[i].UserID == userID {
-			sessions[i].AvatarColor = avatarColor
-			sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-			return s.SaveSessions(sessions)
-		}
+	var file models.SessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, cause
+	}
+	if file.Sessions == nil {
+		file.Sessions = []models.LoginSession{}
+	}
+	return &file, nil
+}
+
+// migrateFile persists a file that was upgraded in memory, after keeping a
+// copy of the original. Failures are logged; the upgraded data is still
+// usable and the migration simply runs again on the next load.
+func (s *SessionStore) migrateFile(original []byte, fromVersion int, file *models.SessionFile) {
+	if s.readOnly {
+		return // The owning instance will migrate it
+	}
+	if err := s.backupBeforeMigration(original, fromVersion); err != nil {
+		fmt.Printf("⚠️ Failed to back up sessions file before migration: %v\n", err)
+		return
+	}
+	if err := s.saveFile(file); err != nil {
+		fmt.Printf("⚠️ Failed to save migrated sessions file: %v\n", err)
+		return
+	}
+	fmt.Printf("⬆️ Migrated sessions file from schema v%d to v%d\n", fromVersion, currentSchemaVersion)
+}
+
+// recoverFromCorruption quarantines a sessions file that failed to parse and
+// restores the newest valid backup in its place. Without a usable backup it
+// returns a SessionFileCorruptError and leaves the damaged file untouched.
+func (s *SessionStore) recoverFromCorruption(data []byte, cause error) (*models.SessionFile, error) {
+	fmt.Printf("⚠️ Sessions file is corrupt: %v\n", cause)
+
+	quarantinePath, err := s.quarantine(data)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to quarantine corrupt sessions file: %v\n", err)
+	}
+
+	file, backupPath, backupErr := s.loadNewestBackup()
+	if backupErr != nil {
+		return nil, &SessionFileCorruptError{Path: s.filePath, QuarantinePath: quarantinePath, Cause: cause}
 	}
 
-	return fmt.Errorf("session not found")
+	if s.readOnly {
+		return file, nil
+	}
+
+	restored, _ := os.ReadFile(backupPath)
+	if err := utils.WriteFileAtomic(s.filePath, restored, 0644); err != nil {
+		fmt.Printf("⚠️ Failed to restore sessions file from backup: %v\n", err)
+	}
+	fmt.Println("🛟 Restored sessions from backup:", backupPath)
+	return file, nil
+}
+
+func (s *SessionStore) SaveSessions(sessions []models.LoginSession) error {
+	return s.update(mutation{"save-all", sourceSessionStore}, func(file *models.SessionFile) error {
+		file.Sessions = cloneSessions(sessions)
+		return nil
+	})
 }
 
-func (s *SessionStore) addOrUpdate(session models.LoginSession) error {
-	// 1. Load all sessions currently stored in JSON
-	sessions, _ := s.LoadSessions()
+// saveFile stamps the current schema and app version onto file, encrypts any
+// plaintext tokens and writes it to the backend. Callers must hold s.mu.
+func (s *SessionStore) saveFile(file *models.SessionFile) error {
+	if err := s.sealTokens(file); err != nil {
+		return err
+	}
+
+	file.SchemaVersion = currentSchemaVersion
+	file.AppVersion = utils.AppVersion
+	if file.Sessions == nil {
+		file.Sessions = []models.LoginSession{}
+	}
 
-	found := false
-	for i, sItem := range sessions {
-		// 2. Check if the same UserID already exists
-		if sItem.UserID == session.UserID {
+	return s.backend.save(file)
+}
 
-			// 3. Only update fields that were previously empty
-			if sItem.Username == "" && session.Username != "" {
-				sessions[i].Username = session.Username
+// DeleteSession moves a session to the recycle bin. It can be brought back
+// with RestoreSession until it is purged.
+func (s *SessionStore) DeleteSession(userID string) error {
+	return s.update(mutation{"delete", sourceSessionStore}, func(file *models.SessionFile) error {
+		updated := []models.LoginSession{}
+		for _, sess := range file.Sessions {
+			if sess.UserID != userID {
+				updated = append(updated, sess)
+				continue
 			}
-			if sItem.LoginToken == "" && session.LoginToken != "" {
-				sessions[i].LoginToken = session.LoginToken
+			file.Trash = removeDeleted(file.Trash, userID)
+			file.Trash = append(file.Trash, models.DeletedSession{
+				LoginSession: sess,
+				DeletedAt:    time.Now().Format(time.RFC3339),
+			})
+		}
+		file.Sessions = updated
+		return nil
+	})
+}
+
+func (s *SessionStore) UpdateAlias(userID string, alias string) error {
+	return s.updateSession(mutation{"update-alias", sourceSessionStore}, userID, func(sess *models.LoginSession) error {
+		sess.Alias = alias
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+func (s *SessionStore) UpdateAvatarImage(userID string, avatarImage string) error {
+	return s.setAvatarImage(sourceSessionStore, userID, avatarImage)
+}
+
+// setAvatarImage backs UpdateAvatarImage for other services.
+func (s *SessionStore) setAvatarImage(source string, userID string, avatarImage string) error {
+	return s.updateSession(mutation{"update-avatar-image", source}, userID, func(sess *models.LoginSession) error {
+		sess.AvatarImage = avatarImage
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+func (s *SessionStore) UpdateAvatarColor(userID string, avatarColor string) error {
+	return s.setAvatarColor(sourceSessionStore, userID, avatarColor)
+}
+
+// setAvatarColor backs UpdateAvatarColor for other services.
+func (s *SessionStore) setAvatarColor(source string, userID string, avatarColor string) error {
+	return s.updateSession(mutation{"update-avatar-color", source}, userID, func(sess *models.LoginSession) error {
+		sess.AvatarColor = avatarColor
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+// hasSession reports whether a session with the given userID is stored.
+func (s *SessionStore) hasSession(userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.current()
+	if err != nil {
+		return false, err
+	}
+	return findSession(file.Sessions, userID) != nil, nil
+}
+
+func (s *SessionStore) addOrUpdate(source string, session models.LoginSession) error {
+	return s.update(mutation{"add-or-update", source}, func(file *models.SessionFile) error {
+		// 1. Check if the same UserID already exists
+		if existing := findSession(file.Sessions, session.UserID); existing != nil {
+
+			// 2. Only update fields that were previously empty
+			if existing.Username == "" && session.Username != "" {
+				existing.Username = session.Username
+			}
+			if existing.LoginToken == "" && session.LoginToken != "" {
+				existing.LoginToken = session.LoginToken
 			}
 
-			// 4. Update alias if user changed it
+			// 3. Update alias if user changed it
 			if session.Alias != "" {
-				sessions[i].Alias = session.Alias
+				existing.Alias = session.Alias
 			}
 
-			// 5. Update timestamp
-			sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
-			found = true
-			break
+			// 4. Update timestamp
+			existing.UpdatedAt = time.Now().Format(time.RFC3339)
+			return nil
 		}
-	}
 
-	// 6. If no existing session found, add it as new
-	if !found {
+		// 5. If no existing session found, add it as new (listed last)
 		session.CreatedAt = time.Now().Format(time.RFC3339)
 		session.UpdatedAt = time.Now().Format(time.RFC3339)
-		sessions = append(sessions, session)
-	}
+		session.SortOrder = nextSortOrder(file.Sessions)
+		file.Sessions = append(file.Sessions, session)
+		return nil
+	})
+}
 
-	// 7. Save everything back to JSON
-	return s.SaveSessions(sessions)
+// renewLoginToken replaces the stored token for userID if it differs from
+// loginToken, comparing fingerprints so the stored token is never decrypted.
+// It reports whether the token changed; unknown users are ignored.
+func (s *SessionStore) renewLoginToken(source string, userID string, loginToken string) (bool, error) {
+	renewed := false
+	err := s.update(mutation{"renew-token", source}, func(file *models.SessionFile) error {
+		sess := findSession(file.Sessions, userID)
+		if sess == nil || sess.TokenFingerprint == tokenFingerprint(loginToken) {
+			return errNoChanges
+		}
+		sess.LoginToken = loginToken
+		sess.NeedsRelogin = false // A fresh token replaces the refused one
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		renewed = true
+		return nil
+	})
+	return renewed, err
 }
 
-// Ensure JSON file exists
-func (s *SessionStore) ensureFile() error {
+// fillMissingUsernames sets the username of every session that has none
+// and appears in usernames (userID → username). It reports whether any
+// session was updated.
+func (s *SessionStore) fillMissingUsernames(source string, usernames map[string]string) (bool, error) {
+	changed := false
+	err := s.update(mutation{"fill-usernames", source}, func(file *models.SessionFile) error {
+		for i, sess := range file.Sessions {
+			if sess.Username != "" {
+				continue
+			}
+			if uname, ok := usernames[sess.UserID]; ok {
+				file.Sessions[i].Username = uname
+				file.Sessions[i].UpdatedAt = time.Now().Format(time.RFC3339)
+				changed = true
+			}
+		}
+		if !changed {
+			return errNoChanges
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// Ensure the app data directory exists
+func (s *SessionStore) ensureDir() error {
 	dir := filepath.Dir(s.filePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return os.MkdirAll(dir, 0755)
+	}
+	return nil
+}
 
+// Ensure JSON file exists
+func (s *SessionStore) ensureFile() error {
 	// Create the directory if missing
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return err
-		}
+	if err := s.ensureDir(); err != nil {
+		return err
+	}
+
+	if s.readOnly {
+		return nil
 	}
 
-	// Now safely create the file if missing
+	// Now safely create the file if missing. A missing file with backups
+	// left behind is treated as unreadable, so LoadSessions can recover them.
 	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
-		empty := []models.LoginSession{}
-		data, _ := json.MarshalIndent(empty, "", "  ")
-		return os.WriteFile(s.filePath, data, 0644)
+		if _, statErr := os.Stat(s.backupPath(1)); statErr == nil {
+			return nil
+		}
+		return s.saveFile(&models.SessionFile{})
 	}
 
 	return nil
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSessionStoreNoticesSameSizeEdit(t *testing.T) {
+	e := newSwitchTestEnv(t)
+	if err := e.store.UpdateAlias(e.session.UserID, "AAAA"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.store.LoadSessions(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the alias in place, keeping the size and modification time
+	path := e.store.filePath
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Replace(data, []byte(`"AAAA"`), []byte(`"BBBB"`), 1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := e.store.LoadSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alias := findSession(sessions, e.session.UserID).Alias; alias != "BBBB" {
+		t.Errorf("alias = %q, want the edited one", alias)
+	}
+
+	// Once the file is older than the timestamp resolution, it's no longer
+	// hashed on every read
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.store.LoadSessions(); err != nil {
+		t.Fatal(err)
+	}
+	if mtimeUnsettled(e.store.cacheStat, e.store.cacheAt) {
+		t.Error("cache still needs hashing for a settled file")
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// maxTagLength keeps tags short enough to render as chips.
+const maxTagLength = 32
+
+// TagCount is a tag with the number of sessions carrying it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// AddTag adds a tag to a session. Tags are matched case-insensitively, so
+// adding "Smurf" to a session tagged "smurf" is a no-op.
+func (s *SessionStore) AddTag(userID string, tag string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	return s.updateSession(mutation{"add-tag", sourceSessionStore}, userID, func(sess *models.LoginSession) error {
+		if hasTag(sess.Tags, tag) {
+			return errNoChanges
+		}
+		sess.Tags = append(sess.Tags, tag)
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+// RemoveTag removes a tag from a session.
+func (s *SessionStore) RemoveTag(userID string, tag string) error {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	return s.updateSession(mutation{"remove-tag", sourceSessionStore}, userID, func(sess *models.LoginSession) error {
+		if !hasTag(sess.Tags, tag) {
+			return errNoChanges
+		}
+		sess.Tags = withoutTag(sess.Tags, tag)
+		sess.UpdatedAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+// ListTags returns every tag in use with how many sessions carry it, most
+// used first. Differently-cased spellings are counted together under the
+// first spelling seen.
+func (s *SessionStore) ListTags() ([]TagCount, error) {
+	sessions, err := s.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]*TagCount{}
+	tags := []*TagCount{}
+	for _, sess := range sessions {
+		for _, tag := range sess.Tags {
+			key := strings.ToLower(tag)
+			if counts[key] == nil {
+				counts[key] = &TagCount{Tag: tag}
+				tags = append(tags, counts[key])
+			}
+			counts[key].Count++
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return strings.ToLower(tags[i].Tag) < strings.ToLower(tags[j].Tag)
+	})
+
+	result := make([]TagCount, len(tags))
+	for i, tag := range tags {
+		result[i] = *tag
+	}
+	return result, nil
+}
+
+// QuerySessionsByTags returns the sessions carrying all of the given tags
+// (matchAll) or any of them. An empty tag list matches every session.
+func (s *SessionStore) QuerySessionsByTags(tags []string, matchAll bool) ([]models.LoginSession, error) {
+	sessions, err := s.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return sessions, nil
+	}
+
+	matched := []models.LoginSession{}
+	for _, sess := range sessions {
+		if matchesTags(sess.Tags, tags, matchAll) {
+			matched = append(matched, sess)
+		}
+	}
+	return matched, nil
+}
+
+// matchesTags reports whether have contains all (matchAll) or any of want.
+func matchesTags(have []string, want []string, matchAll bool) bool {
+	for _, tag := range want {
+		found := hasTag(have, strings.TrimSpace(tag))
+		if matchAll && !found {
+			return false
+		}
+		if !matchAll && found {
+			return true
+		}
+	}
+	return matchAll
+}
+
+// normalizeTag trims a tag and checks it's usable.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", fmt.Errorf("tag is required")
+	}
+	if len([]rune(tag)) > maxTagLength {
+		return "", fmt.Errorf("tag can't be longer than %d characters", maxTagLength)
+	}
+	return tag, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutTag(tags []string, tag string) []string {
+	kept := []string{}
+	for _, t := range tags {
+		if !strings.EqualFold(t, tag) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
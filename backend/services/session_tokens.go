@@ -0,0 +1,424 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// ErrAppLocked is returned by every session read or write while the
+// master-password lock is engaged.
+var ErrAppLocked = errors.New("app is locked, enter the master password to continue")
+
+// unavailableProtector stands in when the platform protector couldn't be set
+// up, so token writes fail loudly instead of falling back to plaintext.
+type unavailableProtector struct {
+	err error
+}
+
+func (p unavailableProtector) Name() string { return "unavailable" }
+
+func (p unavailableProtector) Protect([]byte) ([]byte, error) {
+	return nil, fmt.Errorf("token encryption unavailable: %w", p.err)
+}
+
+func (p unavailableProtector) Unprotect([]byte) ([]byte, error) {
+	return nil, fmt.Errorf("token encryption unavailable: %w", p.err)
+}
+
+// defaultProtector returns the platform protector for tokens stored in dir.
+func defaultProtector(dir string) security.Protector {
+	protector, err := security.DefaultProtector(dir)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to set up token encryption: %v\n", err)
+		return unavailableProtector{err: err}
+	}
+	return protector
+}
+
+// tokenFingerprint returns a short, non-reversible identifier for a token,
+// used to compare tokens without decrypting them.
+func tokenFingerprint(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// allStoredSessions returns pointers to every session in file, including
+// those in the recycle bin, for operations that touch every stored token.
+func allStoredSessions(file *models.SessionFile) []*models.LoginSession {
+	all := make([]*models.LoginSession, 0, len(file.Sessions)+len(file.Trash))
+	for i := range file.Sessions {
+		all = append(all, &file.Sessions[i])
+	}
+	for i := range file.Trash {
+		all = append(all, &file.Trash[i].LoginSession)
+	}
+	return all
+}
+
+// hasPlaintextTokens reports whether any session still stores its token unencrypted.
+func hasPlaintextTokens(file *models.SessionFile) bool {
+	for _, sess := range allStoredSessions(file) {
+		if sess.LoginToken != "" && !security.IsSealed(sess.LoginToken) {
+			return true
+		}
+	}
+	return false
+}
+
+// sealTokens encrypts every plaintext token in file in place, recording its
+// fingerprint first. Callers must hold s.mu.
+func (s *SessionStore) sealTokens(file *models.SessionFile) error {
+	for _, sess := range allStoredSessions(file) {
+		if sess.LoginToken == "" || security.IsSealed(sess.LoginToken) {
+			continue
+		}
+
+		sealed, err := security.Seal(s.protector, sess.LoginToken)
+		if err != nil {
+			return err
+		}
+		sess.TokenFingerprint = tokenFingerprint(sess.LoginToken)
+		sess.LoginToken = sealed
+	}
+	return nil
+}
+
+// revealLoginToken decrypts and returns the stored token for userID. This is
+// the only way a plaintext token leaves the store, and it is meant for the
+// moment a switch writes it into the launcher's session file.
+func (s *SessionStore) revealLoginToken(userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.current()
+	if err != nil {
+		return "", err
+	}
+	sess := findSession(file.Sessions, userID)
+	if sess == nil {
+		return "", fmt.Errorf("session not found")
+	}
+	if sess.LoginToken == "" {
+		return "", fmt.Errorf("no login token stored for this account")
+	}
+
+	return security.Unseal(s.protector, sess.LoginToken)
+}
+
+// lockTokens engages the app lock: sessions become unreadable and the
+// protector (holding the token key) is dropped from memory.
+func (s *SessionStore) lockTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locked = true
+	s.protector = nil
+	s.cache = nil
+}
+
+// unlockTokens lifts the app lock, decrypting tokens with protector from now on.
+func (s *SessionStore) unlockTokens(protector security.Protector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locked = false
+	s.protector = protector
+	s.cache = nil
+}
+
+// activeProtector returns the protector tokens are currently sealed with.
+func (s *SessionStore) activeProtector() security.Protector {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.protector
+}
+
+// resealTokens re-encrypts every stored token, including those in backup
+// copies and other vaults, from the active protector to next, then makes
+// next active. Used when the master password is set, changed or removed.
+func (s *SessionStore) resealTokens(next security.Protector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureWritable(); err != nil {
+		return err
+	}
+	file, err := s.current()
+	if err != nil {
+		return err
+	}
+
+	prev := s.protector
+	reseal := func(token string) (string, error) {
+		plaintext, err := security.Unseal(prev, token)
+		if err != nil {
+			return "", err
+		}
+		return security.Seal(next, plaintext)
+	}
+	undo := func(token string) (string, error) {
+		plaintext, err := security.Unseal(next, token)
+		if err != nil {
+			return "", err
+		}
+		return security.Seal(prev, plaintext)
+	}
+
+	updated := cloneSessionFile(file)
+	for _, sess := range allStoredSessions(updated) {
+		if sess.LoginToken == "" {
+			continue
+		}
+		if sess.LoginToken, err = reseal(sess.LoginToken); err != nil {
+			return fmt.Errorf("failed to re-encrypt token for %s: %w", sess.UserID, err)
+		}
+	}
+
+	// Other vaults share the protector, so they go first: if one fails,
+	// nothing has changed yet that can't be undone
+	others, err := s.resealOtherVaults(reseal, undo)
+	if err != nil {
+		return err
+	}
+
+	s.protector = next
+	if err := s.saveFile(updated); err != nil {
+		s.protector = prev
+		undoReseal(others, undo)
+		return err
+	}
+	s.setCache(updated)
+
+	// Older copies must follow, or they'd stay readable with the old key
+	for _, sessionPath := range append([]string{s.filePath}, others...) {
+		copies, _ := filepath.Glob(sessionPath + ".bak.*")
+		preMigration, _ := filepath.Glob(sessionPath + ".pre-migration-v*")
+		retired, _ := filepath.Glob(sessionPath + ".migrated-*")
+		for _, path := range append(append(copies, preMigration...), retired...) {
+			if err := rewriteTokenValues(path, reseal); err != nil {
+				fmt.Printf("⚠️ Failed to re-encrypt tokens in %s, removing it: %v\n", path, err)
+				os.Remove(path)
+			}
+		}
+
+		quarantined, _ := filepath.Glob(sessionPath + ".corrupt-*")
+		for _, path := range quarantined {
+			if err := rewriteTokenText(path, reseal); err != nil {
+				fmt.Printf("⚠️ Failed to re-encrypt tokens in %s, removing it: %v\n", path, err)
+				os.Remove(path)
+			}
+		}
+
+		retiredDBs, _ := filepath.Glob(kvPathFor(sessionPath) + ".migrated-*")
+		for _, path := range retiredDBs {
+			if err := (&kvBackend{file: path}).rewriteTokens(reseal); err != nil {
+				fmt.Printf("⚠️ Failed to re-encrypt tokens in %s, removing it: %v\n", path, err)
+				os.Remove(path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resealOtherVaults applies reseal to the sessions file of every vault but
+// the open one and returns the files it rewrote. If any fails, the ones
+// already done are put back with undo. Callers must hold s.mu.
+func (s *SessionStore) resealOtherVaults(reseal, undo func(token string) (string, error)) ([]string, error) {
+	done := []string{}
+	for _, path := range vaultSessionFiles() {
+		if path == s.filePath {
+			continue
+		}
+
+		lock, err := utils.TryLockFile(path + ".lock")
+		if err != nil {
+			undoReseal(done, undo)
+			return nil, fmt.Errorf("can't re-encrypt %s while another Epic Switcher window has it open", filepath.Dir(path))
+		}
+		stored, err := rewriteVaultTokens(path, reseal)
+		lock.Unlock()
+		if !stored {
+			continue
+		}
+		if err != nil {
+			undoReseal(done, undo)
+			return nil, fmt.Errorf("failed to re-encrypt tokens in %s: %w", path, err)
+		}
+		done = append(done, path)
+	}
+	return done, nil
+}
+
+// undoReseal rewrites paths back with undo after a failed reseal.
+func undoReseal(paths []string, undo func(token string) (string, error)) {
+	for _, path := range paths {
+		if _, err := rewriteVaultTokens(path, undo); err != nil {
+			fmt.Printf("⚠️ Failed to restore token encryption in %s: %v\n", path, err)
+		}
+	}
+}
+
+// encryptPlaintextTokens upgrades a file written before token encryption:
+// the tokens are sealed and saved (unless a migration save already did), then
+// older copies on disk (rotating and pre-migration backups, and quarantined
+// corrupt copies) are scrubbed so no plaintext token is left behind.
+func (s *SessionStore) encryptPlaintextTokens(file *models.SessionFile) {
+	if s.readOnly {
+		return // The owning instance will encrypt them
+	}
+	if hasPlaintextTokens(file) {
+		if err := s.saveFile(file); err != nil {
+			fmt.Printf("⚠️ Failed to encrypt stored login tokens: %v\n", err)
+			return
+		}
+	}
+	fmt.Println("🔐 Encrypted stored login tokens.")
+
+	copies, _ := filepath.Glob(s.filePath + ".bak.*")
+	preMigration, _ := filepath.Glob(s.filePath + ".pre-migration-v*")
+	for _, path := range append(copies, preMigration...) {
+		if err := s.scrubPlaintextCopy(path); err != nil {
+			fmt.Printf("⚠️ Failed to encrypt tokens in %s: %v\n", path, err)
+		}
+	}
+
+	quarantined, _ := filepath.Glob(s.filePath + ".corrupt-*")
+	for _, path := range quarantined {
+		if err := rewriteTokenText(path, s.sealPlaintext); err != nil {
+			fmt.Printf("⚠️ Failed to encrypt tokens in %s: %v\n", path, err)
+		}
+	}
+}
+
+// scrubPlaintextCopy seals every plaintext token in the JSON file at path,
+// whatever schema version it uses, and rewrites it in place.
+func (s *SessionStore) scrubPlaintextCopy(path string) error {
+	return rewriteTokenValues(path, s.sealPlaintext)
+}
+
+// sealPlaintext seals token unless it already is.
+func (s *SessionStore) sealPlaintext(token string) (string, error) {
+	if security.IsSealed(token) {
+		return token, nil
+	}
+	return security.Seal(s.protector, token)
+}
+
+// rewriteTokenValues applies fn to every non-empty "loginToken" string in
+// the JSON file at path and rewrites the file if anything changed. Files
+// that don't parse are left alone.
+func rewriteTokenValues(path string, fn func(token string) (string, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	changed, err := walkTokenValues(doc, fn)
+	if err != nil || !changed {
+		return err
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(path, out, 0644)
+}
+
+// tokenValue matches a "loginToken" string in JSON text, for files too
+// damaged to parse.
+var tokenValue = regexp.MustCompile(`"loginToken"\s*:\s*("(?:[^"\\]|\\.)*")`)
+
+// rewriteTokenText applies fn to every non-empty "loginToken" string in the
+// file at path without parsing it, so quarantined copies of a corrupt
+// sessions file are kept in step too. Values that don't decode are left
+// alone.
+func rewriteTokenText(path string, fn func(token string) (string, error)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	changed := false
+	out := tokenValue.ReplaceAllFunc(data, func(match []byte) []byte {
+		loc := tokenValue.FindSubmatchIndex(match)
+		var token string
+		if fnErr != nil || json.Unmarshal(match[loc[2]:loc[3]], &token) != nil || token == "" {
+			return match
+		}
+		replaced, err := fn(token)
+		if err != nil {
+			fnErr = err
+			return match
+		}
+		if replaced == token {
+			return match
+		}
+		quoted, _ := json.Marshal(replaced)
+		changed = true
+		return append(append([]byte{}, match[:loc[2]]...), quoted...)
+	})
+	if fnErr != nil || !changed {
+		return fnErr
+	}
+	return utils.WriteFileAtomic(path, out, 0644)
+}
+
+// walkTokenValues walks a decoded JSON document and replaces "loginToken"
+// strings in place, keeping "tokenFingerprint" in step for plaintext input.
+func walkTokenValues(node any, fn func(token string) (string, error)) (bool, error) {
+	changed := false
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if token, ok := value.(string); ok && key == "loginToken" && token != "" {
+				replaced, err := fn(token)
+				if err != nil {
+					return changed, err
+				}
+				if replaced != token {
+					if !security.IsSealed(token) {
+						v["tokenFingerprint"] = tokenFingerprint(token)
+					}
+					v[key] = replaced
+					changed = true
+				}
+				continue
+			}
+			c, err := walkTokenValues(value, fn)
+			changed = changed || c
+			if err != nil {
+				return changed, err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			c, err := walkTokenValues(item, fn)
+			changed = changed || c
+			if err != nil {
+				return changed, err
+			}
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteTokenTextOnDamagedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login_sessions.json.corrupt-20261018-100000")
+	damaged := `{"version": 3, "sessions": [{"userId": "a", "loginToken": "plain\"token"}, {"userId": "b", "loginToken": "", "alias": "loginToken"}, {"userId": "c", "loginTok`
+	if err := os.WriteFile(path, []byte(damaged), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := rewriteTokenText(path, func(token string) (string, error) {
+		return "sealed:" + token, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(damaged, `"plain\"token"`, `"sealed:plain\"token"`, 1)
+	if string(data) != want {
+		t.Errorf("rewritten file:\n%s\nwant:\n%s", data, want)
+	}
+}
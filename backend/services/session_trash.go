@@ -0,0 +1,136 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// GetDeletedSessions lists the sessions in the recycle bin, newest first.
+// Entries past the retention period are left out even before they're purged.
+func (s *SessionStore) GetDeletedSessions() ([]models.DeletedSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	retention := s.settings.trashRetention()
+	deleted := []models.DeletedSession{}
+	for i := len(file.Trash) - 1; i >= 0; i-- {
+		if !isTrashExpired(file.Trash[i], retention) {
+			deleted = append(deleted, file.Trash[i])
+		}
+	}
+	return deleted, nil
+}
+
+// RestoreSession moves a session from the recycle bin back into the list.
+// It fails if the account has been added again since it was deleted.
+func (s *SessionStore) RestoreSession(userID string) error {
+	return s.update(mutation{"restore", sourceSessionStore}, func(file *models.SessionFile) error {
+		deleted := findDeleted(file.Trash, userID)
+		if deleted == nil {
+			return fmt.Errorf("deleted session not found")
+		}
+		if findSession(file.Sessions, userID) != nil {
+			return fmt.Errorf("this account has already been added again")
+		}
+
+		restored := deleted.LoginSession
+		restored.UpdatedAt = time.Now().Format(time.RFC3339)
+		restored.SortOrder = nextSortOrder(file.Sessions)
+		file.Sessions = append(file.Sessions, restored)
+		file.Trash = removeDeleted(file.Trash, userID)
+		return nil
+	})
+}
+
+// PurgeDeletedSession permanently removes one session from the recycle bin.
+func (s *SessionStore) PurgeDeletedSession(userID string) error {
+	return s.update(mutation{"purge", sourceSessionStore}, func(file *models.SessionFile) error {
+		if findDeleted(file.Trash, userID) == nil {
+			return fmt.Errorf("deleted session not found")
+		}
+		file.Trash = removeDeleted(file.Trash, userID)
+		return nil
+	})
+}
+
+// EmptyTrash permanently removes every session in the recycle bin.
+func (s *SessionStore) EmptyTrash() error {
+	return s.update(mutation{"empty-trash", sourceSessionStore}, func(file *models.SessionFile) error {
+		if len(file.Trash) == 0 {
+			return errNoChanges
+		}
+		file.Trash = nil
+		return nil
+	})
+}
+
+// PurgeExpiredTrash removes recycle bin entries older than the configured
+// retention period and returns how many were purged. It runs at startup;
+// every other save purges them as well.
+func (s *SessionStore) PurgeExpiredTrash() (int, error) {
+	purged := 0
+	err := s.update(mutation{"purge-expired", sourceSessionStore}, func(file *models.SessionFile) error {
+		purged = purgeExpiredTrash(file, s.settings.trashRetention())
+		if purged == 0 {
+			return errNoChanges
+		}
+		return nil
+	})
+	if purged > 0 {
+		fmt.Printf("🗑️ Purged %d expired deleted session(s).\n", purged)
+	}
+	return purged, err
+}
+
+// purgeExpiredTrash drops expired entries from file.Trash in place and
+// returns how many were dropped. A retention of 0 keeps everything.
+func purgeExpiredTrash(file *models.SessionFile, retention time.Duration) int {
+	kept := file.Trash[:0]
+	for _, deleted := range file.Trash {
+		if !isTrashExpired(deleted, retention) {
+			kept = append(kept, deleted)
+		}
+	}
+	purged := len(file.Trash) - len(kept)
+	file.Trash = kept
+	return purged
+}
+
+func isTrashExpired(deleted models.DeletedSession, retention time.Duration) bool {
+	if retention <= 0 {
+		return false
+	}
+	deletedAt, err := time.Parse(time.RFC3339, deleted.DeletedAt)
+	if err != nil {
+		return false // Keep entries we can't date rather than lose them
+	}
+	return time.Since(deletedAt) > retention
+}
+
+// findDeleted returns a pointer into trash for the given userID, or nil.
+func findDeleted(trash []models.DeletedSession, userID string) *models.DeletedSession {
+	for i := range trash {
+		if trash[i].UserID == userID {
+			return &trash[i]
+		}
+	}
+	return nil
+}
+
+// removeDeleted returns trash without the entry for userID.
+func removeDeleted(trash []models.DeletedSession, userID string) []models.DeletedSession {
+	kept := []models.DeletedSession{}
+	for _, deleted := range trash {
+		if deleted.UserID != userID {
+			kept = append(kept, deleted)
+		}
+	}
+	return kept
+}
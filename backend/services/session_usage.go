@@ -0,0 +1,74 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+// recordSwitch stamps LastUsedAt and bumps SwitchCount after a successful
+// switch to userID. UpdatedAt is left alone; usage isn't an edit.
+func (s *SessionStore) recordSwitch(source string, userID string) error {
+	return s.updateSession(mutation{"record-switch", source}, userID, func(sess *models.LoginSession) error {
+		sess.LastUsedAt = time.Now().Format(time.RFC3339)
+		sess.SwitchCount++
+		return nil
+	})
+}
+
+// setNeedsRelogin flags or clears userID as needing a fresh login, after
+// the launcher refused or accepted its stored token.
+func (s *SessionStore) setNeedsRelogin(source string, userID string, needsRelogin bool) error {
+	return s.updateSession(mutation{"set-needs-relogin", source}, userID, func(sess *models.LoginSession) error {
+		if sess.NeedsRelogin == needsRelogin {
+			return errNoChanges
+		}
+		sess.NeedsRelogin = needsRelogin
+		return nil
+	})
+}
+
+// GetSessionsByRecentUse lists sessions most recently switched to first.
+// Pinned sessions still come first; never-used sessions keep their custom
+// order at the end.
+func (s *SessionStore) GetSessionsByRecentUse() ([]models.LoginSession, error) {
+	sessions, err := s.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Pinned != sessions[j].Pinned {
+			return sessions[i].Pinned
+		}
+		return lastUsed(sessions[i]).After(lastUsed(sessions[j]))
+	})
+	return sessions, nil
+}
+
+// GetSessionsByFrequentUse lists sessions with the most switches first, most
+// recently used first among equals. Pinned sessions still come first.
+func (s *SessionStore) GetSessionsByFrequentUse() ([]models.LoginSession, error) {
+	sessions, err := s.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Pinned != sessions[j].Pinned {
+			return sessions[i].Pinned
+		}
+		if sessions[i].SwitchCount != sessions[j].SwitchCount {
+			return sessions[i].SwitchCount > sessions[j].SwitchCount
+		}
+		return lastUsed(sessions[i]).After(lastUsed(sessions[j]))
+	})
+	return sessions, nil
+}
+
+// lastUsed parses LastUsedAt, returning the zero time for never-used sessions.
+func lastUsed(sess models.LoginSession) time.Time {
+	t, _ := time.Parse(time.RFC3339, sess.LastUsedAt)
+	return t
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventSessionsChanged is emitted with a SessionChanges payload when the
+// sessions file is changed by something other than this app instance.
+const EventSessionsChanged = "sessions:changed"
+
+// sessionWatchInterval is how often the sessions file is checked for
+// outside changes.
+const sessionWatchInterval = 2 * time.Second
+
+// SessionChanges describes how the stored sessions differ after the file
+// was changed on disk.
+type SessionChanges struct {
+	Added   []models.LoginSession `json:"added"`
+	Changed []models.LoginSession `json:"changed"`
+	Removed []string              `json:"removed"`
+}
+
+// SetSessionStoreContext provides a way for other packages to set the
+// context without exposing it to the frontend bindings. It also starts
+// watching the sessions file for outside changes.
+func SetSessionStoreContext(s *SessionStore, ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(sessionWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkForChanges()
+			}
+		}
+	}()
+}
+
+// checkForChanges reloads the sessions file if it changed on disk. Any
+// difference is reported by current.
+func (s *SessionStore) checkForChanges() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locked || s.cache == nil {
+		return // Nothing shown to compare against
+	}
+	s.current()
+}
+
+// notifyReload emits EventSessionsChanged if a reload from disk changed
+// the sessions. Callers must hold s.mu.
+func (s *SessionStore) notifyReload(prev, next *models.SessionFile) {
+	if s.ctx == nil || prev == nil || next == nil {
+		return
+	}
+
+	changes := diffSessions(prev.Sessions, next.Sessions)
+	if len(changes.Added) == 0 && len(changes.Changed) == 0 && len(changes.Removed) == 0 {
+		return
+	}
+	runtime.EventsEmit(s.ctx, EventSessionsChanged, changes)
+}
+
+// diffSessions compares two session lists by UserID.
+func diffSessions(prev, next []models.LoginSession) SessionChanges {
+	changes := SessionChanges{
+		Added:   []models.LoginSession{},
+		Changed: []models.LoginSession{},
+		Removed: []string{},
+	}
+
+	for _, sess := range next {
+		old := findSession(prev, sess.UserID)
+		switch {
+		case old == nil:
+			changes.Added = append(changes.Added, cloneSession(sess))
+		case !reflect.DeepEqual(*old, sess):
+			changes.Changed = append(changes.Changed, cloneSession(sess))
+		}
+	}
+	for _, sess := range prev {
+		if findSession(next, sess.UserID) == nil {
+			changes.Removed = append(changes.Removed, sess.UserID)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// SettingsService loads and saves backend settings (app_settings.json).
+// Other services read it through the unexported accessors; a nil
+// *SettingsService behaves as default settings.
+type SettingsService struct {
+	filePath string
+
+	mu       sync.Mutex
+	settings models.AppSettings
+}
+
+// NewSettingsService loads saved settings, falling back to defaults.
+func NewSettingsService() *SettingsService {
+	s := &SettingsService{
+		filePath: filepath.Join(utils.GetAppDataPath(), "app_settings.json"),
+		settings: models.DefaultAppSettings(),
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err == nil {
+		// Unmarshal over the defaults, so settings added later keep theirs
+		if err := json.Unmarshal(data, &s.settings); err != nil {
+			fmt.Printf("⚠️ Failed to parse settings, using defaults: %v\n", err)
+			s.settings = models.DefaultAppSettings()
+		}
+	} else if !os.IsNotExist(err) {
+		fmt.Printf("⚠️ Failed to read settings, using defaults: %v\n", err)
+	}
+
+	return s
+}
+
+// GetSettings returns the current settings.
+func (s *SettingsService) GetSettings() models.AppSettings {
+	if s == nil {
+		return models.DefaultAppSettings()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings
+}
+
+// UpdateSettings validates and saves new settings.
+func (s *SettingsService) UpdateSettings(settings models.AppSettings) error {
+	if settings.TrashRetentionDays < 0 {
+		return fmt.Errorf("trash retention can't be negative")
+	}
+	if settings.VerifySwitchTimeoutSeconds < 0 {
+		return fmt.Errorf("switch verification timeout can't be negative")
+	}
+	if settings.HookTimeoutSeconds < 0 {
+		return fmt.Errorf("hook timeout can't be negative")
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return err
+	}
+	if err := utils.WriteFileAtomic(s.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	s.settings = settings
+	return nil
+}
+
+// trashRetention returns how long deleted sessions are kept (0 = forever).
+func (s *SettingsService) trashRetention() time.Duration {
+	return time.Duration(s.GetSettings().TrashRetentionDays) * 24 * time.Hour
+}
+
+// switchVerification reports whether switches should be verified and for
+// how long (0 falls back to the default).
+func (s *SettingsService) switchVerification() (bool, time.Duration) {
+	settings := s.GetSettings()
+	timeout := settings.VerifySwitchTimeoutSeconds
+	if timeout == 0 {
+		timeout = models.DefaultAppSettings().VerifySwitchTimeoutSeconds
+	}
+	return settings.VerifySwitch, time.Duration(timeout) * time.Second
+}
+
+// switchHooks returns the configured hooks and their timeout (0 falls back
+// to the default).
+func (s *SettingsService) switchHooks() switchHooks {
+	settings := s.GetSettings()
+	timeout := settings.HookTimeoutSeconds
+	if timeout == 0 {
+		timeout = models.DefaultAppSettings().HookTimeoutSeconds
+	}
+	return switchHooks{
+		pre:               strings.TrimSpace(settings.PreSwitchHook),
+		post:              strings.TrimSpace(settings.PostSwitchHook),
+		timeout:           time.Duration(timeout) * time.Second,
+		abortOnPreFailure: settings.AbortOnPreHookFailure,
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/helper"
+	"epic-games-account-switcher/backend/models"
+)
+
+// Hooks run around a switch, see AppSettings.PreSwitchHook.
+const (
+	HookPreSwitch  = "pre-switch"
+	HookPostSwitch = "post-switch"
+)
+
+// Steps a switch reports for its hooks.
+const (
+	SwitchStepPreHook  = "pre-hook"
+	SwitchStepPostHook = "post-hook"
+)
+
+// Actions a hook runs for, passed to it as EPIC_SWITCHER_ACTION.
+const (
+	hookActionSwitch    = "switch"
+	hookActionMoveAside = "move-aside"
+)
+
+// maxHookOutput caps how much of a hook's output is kept.
+const maxHookOutput = 64 * 1024
+
+// hookWaitDelay is how long a killed hook gets to release its output. A
+// child the shell started can keep it open after the shell is gone.
+const hookWaitDelay = 2 * time.Second
+
+// HookResult describes one run of a hook, with its combined stdout and
+// stderr.
+type HookResult struct {
+	Hook       string `json:"hook"`
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exitCode"`
+	Output     string `json:"output,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	TimedOut   bool   `json:"timedOut,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// switchHooks are the hooks configured in settings.
+type switchHooks struct {
+	pre               string
+	post              string
+	timeout           time.Duration
+	abortOnPreFailure bool
+}
+
+// runPre runs the pre-switch hook, if one is set. The error is only
+// returned when a failed hook should stop the switch.
+func (h switchHooks) runPre(ctx context.Context, action string, session *models.LoginSession) (*HookResult, error) {
+	if h.pre == "" {
+		return nil, nil
+	}
+	result, err := runHook(ctx, HookPreSwitch, h.pre, h.timeout, action, session)
+	if err != nil && h.abortOnPreFailure {
+		return result, err
+	}
+	return result, nil
+}
+
+// runPost runs the post-switch hook, if one is set. Its failure is only
+// reported, since the switch is already done.
+func (h switchHooks) runPost(ctx context.Context, action string, session *models.LoginSession) *HookResult {
+	if h.post == "" {
+		return nil
+	}
+	result, _ := runHook(ctx, HookPostSwitch, h.post, h.timeout, action, session)
+	return result
+}
+
+// runHook runs command through the platform shell, killing it after
+// timeout or once ctx is done. The account is passed in EPIC_SWITCHER_*
+// environment variables; session may be nil when it isn't known.
+func runHook(ctx context.Context, hook, command string, timeout time.Duration, action string, session *models.LoginSession) (*HookResult, error) {
+	if session == nil {
+		session = &models.LoginSession{}
+	}
+	fmt.Printf("🪝 Running %s hook: %s\n", hook, command)
+
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &cappedBuffer{max: maxHookOutput}
+	cmd := helper.NewShellCommand(hookCtx, command)
+	cmd.Env = append(os.Environ(),
+		"EPIC_SWITCHER_HOOK="+hook,
+		"EPIC_SWITCHER_ACTION="+action,
+		"EPIC_SWITCHER_ALIAS="+session.Alias,
+		"EPIC_SWITCHER_USER_ID="+session.UserID,
+		"EPIC_SWITCHER_USERNAME="+session.Username,
+	)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = hookWaitDelay
+
+	start := time.Now()
+	runErr := cmd.Run()
+	result := &HookResult{
+		Hook:       hook,
+		Command:    command,
+		ExitCode:   -1,
+		Output:     output.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if out := strings.TrimSpace(result.Output); out != "" {
+		fmt.Println(out)
+	}
+
+	var err error
+	switch {
+	case errors.Is(hookCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil:
+		result.TimedOut = true
+		err = fmt.Errorf("%s hook timed out after %s", hook, timeout)
+	case ctx.Err() != nil:
+		err = fmt.Errorf("%s hook was cancelled", hook)
+	case runErr != nil && result.ExitCode > 0:
+		err = fmt.Errorf("%s hook exited with code %d", hook, result.ExitCode)
+	case runErr != nil:
+		err = fmt.Errorf("%s hook failed: %w", hook, runErr)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		fmt.Printf("⚠️ %s\n", err)
+		return result, err
+	}
+	fmt.Printf("✅ %s hook finished in %dms\n", hook, result.DurationMs)
+	return result, nil
+}
+
+// cappedBuffer keeps the first max bytes written to it and drops the rest.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.max - c.buf.Len(); room < len(p) {
+		c.buf.Write(p[:max(room, 0)])
+		c.truncated = true
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+func (c *cappedBuffer) String() string {
+	if c.truncated {
+		return c.buf.String() + "\n[output truncated]"
+	}
+	return c.buf.String()
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+// Steps SwitchAndLaunch adds around a switch.
+const (
+	SwitchStepCheckGame    = "check-game"
+	SwitchStepWaitLauncher = "wait-launcher"
+	SwitchStepLaunchGame   = "launch-game"
+)
+
+// InstalledGame is a game installed through the Epic Games Launcher.
+type InstalledGame struct {
+	AppName     string `json:"appName"`
+	DisplayName string `json:"displayName"`
+}
+
+// ListInstalledGames lists the fully installed games SwitchAndLaunch can
+// start.
+func (s *SwitchService) ListInstalledGames() ([]InstalledGame, error) {
+	games, err := readInstalledGames(s.preflight.manifestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed games: %w", err)
+	}
+
+	installed := []InstalledGame{}
+	for _, game := range games {
+		if !game.Incomplete {
+			installed = append(installed, InstalledGame{AppName: game.AppName, DisplayName: game.name()})
+		}
+	}
+	return installed, nil
+}
+
+// SwitchAndLaunch switches to the account and, once the launcher has signed
+// in, launches the game with the given AppName through the launcher.
+//
+// A game that isn't installed fails the call before anything is touched.
+// Each phase is reported as an EventSwitchStage event and in the returned
+// report. If the switch went through but the launcher never became ready or
+// the game didn't start, the switch is kept and only the error is returned.
+func (s *SwitchService) SwitchAndLaunch(userID string, appName string) (*SwitchReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), switchTimeout)
+	defer cancel()
+	end, err := s.beginSwitch(cancel)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	tx := newSwitchTx(userID, s.emitStage)
+	tx.report.AppName = appName
+
+	// 1️⃣ Make sure the game is installed
+	err = tx.run(SwitchStepCheckGame, func() error {
+		games, err := readInstalledGames(s.preflight.manifestsDir)
+		if err != nil {
+			return fmt.Errorf("failed to read installed games: %w", err)
+		}
+		game := findInstalledGame(games, appName)
+		if game == nil {
+			return fmt.Errorf("%s is not installed", appName)
+		}
+		if game.Incomplete {
+			return fmt.Errorf("%s is not fully installed", game.name())
+		}
+		if _, err := os.Stat(game.InstallLocation); err != nil {
+			return fmt.Errorf("install folder of %s is missing: %s", game.name(), game.InstallLocation)
+		}
+		tx.report.AppName = game.AppName
+		return nil
+	})
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	// 2️⃣ Switch accounts, relaunching minimized since the game is what
+	// the user wants to see
+	sessions, err := s.sessionStore.LoadSessions()
+	if err != nil {
+		return tx.fail(fmt.Errorf("failed to load sessions: %w", err))
+	}
+	session := findSession(sessions, userID)
+	if session == nil {
+		return tx.fail(fmt.Errorf("account not found: %s", userID))
+	}
+	if err := s.runSwitch(ctx, tx, *session, true, false); err != nil {
+		return tx.fail(err)
+	}
+
+	// 3️⃣ Wait until the launcher has signed in, reusing the switch's own
+	// verification when it ran
+	err = tx.run(SwitchStepWaitLauncher, func() error {
+		verification := tx.report.Verification
+		if verification == nil {
+			_, timeout := s.sessionStore.settings.switchVerification()
+			verification = s.verifyLogin(ctx, userID, tx.relaunched, timeout)
+			tx.report.Verification = verification
+		}
+		switch verification.Result {
+		case VerifyVerified:
+			return nil
+		case VerifyRejected:
+			return fmt.Errorf("the launcher didn't sign in: %s", verification.Reason)
+		default:
+			return fmt.Errorf("the launcher wasn't ready in time: %s", verification.Reason)
+		}
+	})
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	// 4️⃣ Hand the launch URI to the running launcher, the same way the
+	// desktop shortcuts it creates do
+	err = tx.run(SwitchStepLaunchGame, func() error {
+		uri := gameLaunchURI(tx.report.AppName)
+		fmt.Println("🎮 Launching:", uri)
+		if err := s.processes.Start(utils.GetEpicLauncherPath(), uri); err != nil {
+			return fmt.Errorf("failed to launch %s: %w", tx.report.AppName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return tx.fail(err)
+	}
+	return tx.report, nil
+}
+
+// gameLaunchURI returns the launcher URI that starts the game.
+func gameLaunchURI(appName string) string {
+	return "com.epicgames.launcher://apps/" + url.PathEscape(appName) + "?action=launch"
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"epic-games-account-switcher/backend/helper"
+	"epic-games-account-switcher/backend/utils"
+)
+
+// SwitchStepPreflight checks that closing the launcher won't kill a game or
+// interrupt a download.
+const SwitchStepPreflight = "preflight"
+
+// Kinds of SwitchBlocker.
+const (
+	BlockerGame     = "game"
+	BlockerDownload = "download"
+)
+
+// ErrSwitchBlocked is returned when a switch is refused because of
+// blockers; pass force to switch anyway.
+var ErrSwitchBlocked = errors.New("switch blocked")
+
+// installStartMarkers and installEndMarkers are phrases expected in the
+// launcher's log when a download, install, update or verification of a game
+// starts and stops. The last one logged for a game tells whether it's still
+// going. The launcher's log format isn't documented and changes between
+// releases, so this is a best-effort guess: a download can go unnoticed.
+var (
+	installStartMarkers = []string{
+		"starting download",
+		"starting install",
+		"starting update",
+		"starting verification",
+		"resuming download",
+		"resuming install",
+	}
+	installEndMarkers = []string{
+		"download complete",
+		"install complete",
+		"installation complete",
+		"update complete",
+		"verification complete",
+		"download paused",
+		"install paused",
+		"install cancelled",
+		"install canceled",
+		"install failed",
+		"download failed",
+	}
+)
+
+// SwitchBlocker is something a switch would interrupt by closing the
+// launcher: a running game or an active download/install. Downloads are
+// found on a best-effort basis (see installStartMarkers).
+type SwitchBlocker struct {
+	Kind        string `json:"kind"`
+	AppName     string `json:"appName,omitempty"`
+	DisplayName string `json:"displayName"`
+	Process     string `json:"process,omitempty"`
+	PID         int    `json:"pid,omitempty"`
+}
+
+// switchPreflight finds blockers from the installed game manifests and the
+// launcher's current log.
+type switchPreflight struct {
+	manifestsDir string
+	logsDir      string
+}
+
+func newSwitchPreflight() *switchPreflight {
+	return &switchPreflight{
+		manifestsDir: utils.GetEpicManifestsPath(),
+		logsDir:      utils.GetEpicLogsPath(),
+	}
+}
+
+// CheckSwitchBlockers lists running games and active downloads or installs
+// that switching would interrupt. Running games are found by the launch
+// executable in their manifest; downloads only on a best-effort basis.
+func (s *SwitchService) CheckSwitchBlockers() ([]SwitchBlocker, error) {
+	return s.preflight.blockers(s.processes)
+}
+
+func (p *switchPreflight) blockers(pc helper.ProcessController) ([]SwitchBlocker, error) {
+	games, err := readInstalledGames(p.manifestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed games: %w", err)
+	}
+	procs, err := pc.List()
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := []SwitchBlocker{}
+
+	// 1️⃣ Games whose launch executable is running
+	for _, game := range games {
+		if proc := findGameProcess(game, procs); proc != nil {
+			blockers = append(blockers, SwitchBlocker{
+				Kind:        BlockerGame,
+				AppName:     game.AppName,
+				DisplayName: game.name(),
+				Process:     proc.Name,
+				PID:         proc.PID,
+			})
+		}
+	}
+
+	// 2️⃣ Downloads and installs, which only run while the launcher does
+	launcherRunning := false
+	for _, proc := range procs {
+		if strings.EqualFold(proc.Name, launcherProcess) {
+			launcherRunning = true
+			break
+		}
+	}
+	if launcherRunning {
+		for _, game := range activeInstalls(filepath.Join(p.logsDir, launcherLogName), games) {
+			blockers = append(blockers, SwitchBlocker{
+				Kind:        BlockerDownload,
+				AppName:     game.AppName,
+				DisplayName: game.name(),
+			})
+		}
+	}
+	return blockers, nil
+}
+
+// findGameProcess returns the first running process started from game's
+// launch executable. A game that hands over to another binary isn't seen
+// once the launch executable exits.
+func findGameProcess(game installedGame, procs []helper.Process) *helper.Process {
+	exe := game.launchImageName()
+	if exe == "" {
+		return nil
+	}
+	for i := range procs {
+		if strings.EqualFold(procs[i].Name, exe) {
+			return &procs[i]
+		}
+	}
+	return nil
+}
+
+// activeInstalls returns the games whose last install marker in the
+// launcher log at path is a start marker. Lines are matched to a game by
+// its AppName as a whole word; display names are too loose, since they
+// also show up in store and library lines.
+func activeInstalls(path string, games []installedGame) []installedGame {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	active := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.ToLower(scanner.Text())
+		started := containsAny(line, installStartMarkers)
+		if !started && !containsAny(line, installEndMarkers) {
+			continue
+		}
+		for _, game := range games {
+			if mentionsWord(line, strings.ToLower(game.AppName)) {
+				active[game.AppName] = started
+			}
+		}
+	}
+
+	found := []installedGame{}
+	for _, game := range games {
+		if active[game.AppName] {
+			found = append(found, game)
+		}
+	}
+	return found
+}
+
+// describeBlockers summarizes blockers for an error message.
+func describeBlockers(blockers []SwitchBlocker) string {
+	parts := []string{}
+	for _, b := range blockers {
+		switch b.Kind {
+		case BlockerGame:
+			parts = append(parts, b.DisplayName+" is running")
+		case BlockerDownload:
+			parts = append(parts, b.DisplayName+" seems to be downloading or installing")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// mentionsWord reports whether word occurs in s with no letter, digit or
+// underscore right before or after it.
+func mentionsWord(s, word string) bool {
+	for i := 0; word != "" && i+len(word) <= len(s); {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventSwitchStage is emitted with a SwitchStageEvent payload as each step
+// of a switch starts and ends.
+const EventSwitchStage = "switch:stage"
+
+// switchTimeout bounds a whole switch started from the frontend,
+// verification included.
+const switchTimeout = 2 * time.Minute
+
+// Statuses reported in a SwitchStageEvent.
+const (
+	SwitchStageStarted   = "started"
+	SwitchStageDone      = "done"
+	SwitchStageFailed    = "failed"
+	SwitchStageCancelled = "cancelled"
+)
+
+// SwitchStepRollback is reported when a failed switch is being undone.
+const SwitchStepRollback = "rollback"
+
+// ErrSwitchCancelled is returned when a switch is cancelled or times out
+// before the new session was written.
+var ErrSwitchCancelled = errors.New("switch cancelled")
+
+// SwitchStageEvent reports progress of a switch. ElapsedMs is the time
+// spent in this stage and TotalMs the time since the switch began.
+type SwitchStageEvent struct {
+	UserID    string `json:"userId"`
+	Stage     string `json:"stage"`
+	Status    string `json:"status"`
+	ElapsedMs int64  `json:"elapsedMs"`
+	TotalMs   int64  `json:"totalMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// switchRun is the switch in progress. Once committed, the new session has
+// been written and cancelling would leave things half done.
+type switchRun struct {
+	cancel    context.CancelFunc
+	committed bool
+}
+
+// setContext sets the context for the service (unexported to hide from Wails bindings).
+func (s *SwitchService) setContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+}
+
+// SetSwitchServiceContext provides a way for other packages to set the context
+// without exposing it to the frontend bindings.
+func SetSwitchServiceContext(s *SwitchService, ctx context.Context) {
+	s.setContext(ctx)
+}
+
+// CancelSwitch aborts the switch in progress, as long as it hasn't written
+// the new session yet. The launcher is put back as it was and SwitchAccount
+// returns ErrSwitchCancelled.
+func (s *SwitchService) CancelSwitch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running == nil {
+		return fmt.Errorf("no switch in progress")
+	}
+	if s.running.committed {
+		return fmt.Errorf("the new session is already written, the switch can't be cancelled")
+	}
+	s.running.cancel()
+	fmt.Println("🛑 Switch cancel requested")
+	return nil
+}
+
+// beginSwitch registers a switch as running, refusing a second one. The
+// returned function must be called when it ends.
+func (s *SwitchService) beginSwitch(cancel context.CancelFunc) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running != nil {
+		return nil, fmt.Errorf("a switch is already in progress")
+	}
+	s.running = &switchRun{cancel: cancel}
+	return func() {
+		s.mu.Lock()
+		s.running = nil
+		s.mu.Unlock()
+	}, nil
+}
+
+// commitSwitch is the point of no return: it fails with ErrSwitchCancelled
+// if ctx is done, and otherwise stops CancelSwitch from taking effect.
+func (s *SwitchService) commitSwitch(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := switchCancelled(ctx); err != nil {
+		return err
+	}
+	if s.running != nil {
+		s.running.committed = true
+	}
+	return nil
+}
+
+// switchCancelled returns ErrSwitchCancelled, saying why, once ctx is done.
+func switchCancelled(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%w: timed out", ErrSwitchCancelled)
+	default:
+		return ErrSwitchCancelled
+	}
+}
+
+// emitStage sends a SwitchStageEvent to the frontend, if there is one.
+func (s *SwitchService) emitStage(event SwitchStageEvent) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, EventSwitchStage, event)
+}
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"epic-games-account-switcher/backend/helper"
@@ -13,8 +16,14 @@ import (
 
 const rememberMeSection = "[RememberMe]"
 
+// launcherProcess is the Epic Games Launcher's image name.
+const launcherProcess = "EpicGamesLauncher.exe"
+
+// launcherExitTimeout is how long to wait for a killed launcher to exit.
+const launcherExitTimeout = 8 * time.Second
+
 // auxiliaryLauncherProcesses are helper/child processes spawned by the Epic
-// Games Launcher. A forceful taskkill of EpicGamesLauncher.exe leaves these
+// Games Launcher. A forceful kill of EpicGamesLauncher.exe leaves these
 // orphaned, and the next launcher instance stalls for 1-2 minutes waiting on
 // the dead IPC/overlay connections they held before it becomes responsive.
 var auxiliaryLauncherProcesses = []string{
@@ -25,107 +34,266 @@ var auxiliaryLauncherProcesses = []string{
 	"CrashReportClient.exe",
 }
 
-type SwitchService struct{}
+type SwitchService struct {
+	sessionStore *SessionStore
+	processes    helper.ProcessController
+	verifier     *switchVerifier
+	preflight    *switchPreflight
+
+	mu      sync.Mutex
+	ctx     context.Context
+	running *switchRun
+}
+
+func NewSwitchService(sessionStore *SessionStore) *SwitchService {
+	return NewSwitchServiceWithController(sessionStore, helper.NewProcessController())
+}
 
-func NewSwitchService() *SwitchService {
-	return &SwitchService{}
+// NewSwitchServiceWithController creates a SwitchService that stops and
+// starts the launcher through pc, e.g. a helper.FakeProcessController.
+func NewSwitchServiceWithController(sessionStore *SessionStore, pc helper.ProcessController) *SwitchService {
+	return &SwitchService{sessionStore: sessionStore, processes: pc, verifier: newSwitchVerifier(), preflight: newSwitchPreflight()}
 }
 
 // SwitchAccount replaces the current Epic Games session file with a new one,
 // after closing and restarting the launcher. When launchMinimized is true,
 // the launcher is relaunched with "-silent" (hidden/background); otherwise
 // it relaunches with its normal, visible window.
-func (s *SwitchService) SwitchAccount(session models.LoginSession, launchMinimized bool) error {
-	fmt.Println("🔹 Closing Epic Games Launcher before switching accounts...")
+//
+// The switch runs as a sequence of undoable steps. If one fails, the
+// session file is restored from a snapshot taken up front and a launcher
+// that was running is relaunched on the original account; the returned
+// SwitchError says what was rolled back. Progress is emitted as
+// EventSwitchStage events, and CancelSwitch aborts it up until the new
+// session is written.
+//
+// A switch that would kill a running game or interrupt a download fails
+// with ErrSwitchBlocked, listing them in the report, unless force is true.
+// The pre- and post-switch hooks from settings run around it.
+func (s *SwitchService) SwitchAccount(session models.LoginSession, launchMinimized bool, force bool) (*SwitchReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), switchTimeout)
+	defer cancel()
+	return s.switchAccount(ctx, session, launchMinimized, force)
+}
 
-	// 1️⃣ Force-kill the launcher. Epic Games Launcher doesn't respond to a
-	// graceful taskkill request, so attempting one first only adds dead wait
-	// time before falling back to this anyway.
-	launcherWasRunning := isProcessRunning("EpicGamesLauncher.exe")
-	if launcherWasRunning {
-		killCmd := helper.NewCommand("taskkill", "/IM", "EpicGamesLauncher.exe", "/F")
-		if err := killCmd.Run(); err != nil {
-			errStr := err.Error()
-			if !strings.Contains(errStr, "not found") && !strings.Contains(errStr, "exit status 128") {
-				return fmt.Errorf("failed to close Epic Games Launcher: %w", err)
+// switchAccount does the work of SwitchAccount. Cancelling ctx, or its
+// deadline passing, aborts the switch if the new session isn't written yet
+// and otherwise only cuts verification short.
+func (s *SwitchService) switchAccount(ctx context.Context, session models.LoginSession, launchMinimized bool, force bool) (*SwitchReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	end, err := s.beginSwitch(cancel)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	tx := newSwitchTx(session.UserID, s.emitStage)
+	if err := s.runSwitch(ctx, tx, session, launchMinimized, force); err != nil {
+		return tx.fail(err)
+	}
+	return tx.report, nil
+}
+
+// runSwitch runs the steps of a switch on tx. On error the caller rolls tx
+// back; on success its undos are dropped, so a step run on tx afterwards
+// can fail without undoing the switch.
+func (s *SwitchService) runSwitch(ctx context.Context, tx *switchTx, session models.LoginSession, launchMinimized bool, force bool) error {
+	// Decrypt the stored token up front, so a locked or unreadable store
+	// fails before the launcher is touched.
+	loginToken, err := s.sessionStore.revealLoginToken(session.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to read login token: %w", err)
+	}
+
+	// 1️⃣ Snapshot the session file, so any failure below can put it back
+	path := utils.GetEpicLoginSessionPath()
+	if path == "" {
+		return fmt.Errorf("could not find Epic Games session path")
+	}
+	snapshot, err := snapshotFile(path)
+	if err != nil {
+		return err
+	}
+
+	launcherPath := utils.GetEpicLauncherPath()
+	launchArgs := []string{}
+	if launchMinimized {
+		launchArgs = append(launchArgs, "-silent")
+	}
+
+	// 2️⃣ Make sure closing the launcher won't kill a game or interrupt a
+	// download, unless forced. A check that can't run doesn't block.
+	err = tx.run(SwitchStepPreflight, func() error {
+		blockers, err := s.preflight.blockers(s.processes)
+		if err != nil {
+			fmt.Printf("⚠️ Skipped checking for running games and downloads: %v\n", err)
+			return nil
+		}
+		tx.report.Blockers = blockers
+		if len(blockers) == 0 {
+			return nil
+		}
+		if force {
+			fmt.Println("⚠️ Switching anyway (forced):", describeBlockers(blockers))
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrSwitchBlocked, describeBlockers(blockers))
+	})
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ Run the user's pre-switch hook, once the switch is known to go
+	// ahead. A failed hook only stops the switch when AbortOnPreHookFailure
+	// is set.
+	hooks := s.sessionStore.settings.switchHooks()
+	if hooks.pre != "" {
+		err = tx.run(SwitchStepPreHook, func() error {
+			result, err := hooks.runPre(ctx, hookActionSwitch, &session)
+			tx.report.Hooks = append(tx.report.Hooks, *result)
+			if cancelErr := switchCancelled(ctx); cancelErr != nil {
+				return cancelErr
 			}
+			return err
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		if !waitForProcessExit("EpicGamesLauncher.exe", 8*time.Second) {
-			return fmt.Errorf("timeout waiting for Epic Games Launcher to close")
+	fmt.Println("🔹 Closing Epic Games Launcher before switching accounts...")
+
+	// 4️⃣ Force-kill the launcher. Epic Games Launcher doesn't respond to a
+	// graceful close request, so attempting one first only adds dead wait
+	// time before falling back to this anyway.
+	launcherWasRunning := false
+	err = tx.run(SwitchStepCloseLauncher, func() error {
+		if err := switchCancelled(ctx); err != nil {
+			return err
+		}
+		var err error
+		launcherWasRunning, err = closeLauncher(ctx, s.processes)
+		if launcherWasRunning {
+			// Registered even on failure, since it may have been killed already
+			tx.onRollback("relaunch Epic Games Launcher on the previous account", func() error {
+				return s.processes.Start(launcherPath, launchArgs...)
+			})
 		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if launcherWasRunning {
 		fmt.Println("✅ Epic Games Launcher closed.")
 	} else {
 		fmt.Println("ℹ️ Epic Games Launcher was already closed, continuing...")
 	}
 
-	// 2️⃣ Clean up any orphaned helper processes left behind by the launcher.
-	// taskkill blocks until each process is actually terminated, so no extra
+	// 5️⃣ Clean up any orphaned helper processes left behind by the launcher.
+	// Terminate waits until each process is actually gone, so no extra
 	// wait is needed before the OS has released their handles/sockets.
-	killAuxiliaryProcesses()
+	err = tx.run(SwitchStepKillHelpers, func() error {
+		if err := switchCancelled(ctx); err != nil {
+			return err
+		}
+		killAuxiliaryProcesses(s.processes)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-	// 3️⃣ Merge the new session token into the existing session file instead
+	// 6️⃣ Merge the new session token into the existing session file instead
 	// of overwriting it, so unrelated launcher settings (e.g. Preferences) survive.
-	path := utils.GetEpicLoginSessionPath()
-	if err := upsertRememberMeSection(path, session.LoginToken); err != nil {
-		return fmt.Errorf("failed to write session file: %w", err)
+	// Past this point the switch can no longer be cancelled.
+	err = tx.run(SwitchStepWriteSession, func() error {
+		if err := s.commitSwitch(ctx); err != nil {
+			return err
+		}
+		// Registered first, since a failed write may still have changed the file
+		tx.onRollback("restore "+filepath.Base(path), snapshot.restore)
+		if err := upsertRememberMeSection(path, loginToken); err != nil {
+			return fmt.Errorf("failed to write session file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	fmt.Println("✅ New session written to:", path)
 
-	// 4️⃣ Relaunch Epic Games Launcher
-	launcherPath := utils.GetEpicLauncherPath()
+	// 7️⃣ Relaunch Epic Games Launcher
 	fmt.Println("🔹 Re-launching Epic Games Launcher:", launcherPath)
+	tx.relaunched = s.verifier.mark()
+	err = tx.run(SwitchStepRelaunch, func() error {
+		if err := s.processes.Start(launcherPath, launchArgs...); err != nil {
+			return fmt.Errorf("failed to relaunch Epic Games Launcher: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println("✅ Epic Games Launcher started successfully.")
 
-	launchArgs := []string{}
-	if launchMinimized {
-		launchArgs = append(launchArgs, "-silent")
+	// 8️⃣ Optionally confirm the launcher really signed in as this account.
+	// A rejected login isn't rolled back: the launcher is left on its login
+	// screen and the session is flagged for re-login.
+	if verify, timeout := s.sessionStore.settings.switchVerification(); verify {
+		tx.run(SwitchStepVerify, func() error {
+			tx.report.Verification = s.verifyLogin(ctx, session.UserID, tx.relaunched, timeout)
+			return nil
+		})
 	}
 
-	startCmd := helper.NewCommand(launcherPath, launchArgs...)
-	startCmd.Stdout = os.Stdout
-	startCmd.Stderr = os.Stderr
+	// 9️⃣ Record usage, unless the launcher turned the account down. The
+	// switch itself already succeeded, so a failure here is only logged.
+	if tx.report.Verification == nil || tx.report.Verification.Result != VerifyRejected {
+		if err := s.sessionStore.recordSwitch(sourceSwitch, session.UserID); err != nil {
+			fmt.Printf("⚠️ Failed to record account usage: %v\n", err)
+		}
+	}
 
-	if err := startCmd.Start(); err != nil {
-		return fmt.Errorf("failed to relaunch Epic Games Launcher: %w", err)
+	// 🔟 Run the user's post-switch hook. It gets its own timeout, since the
+	// switch's deadline may be nearly spent by verification.
+	if hooks.post != "" {
+		tx.run(SwitchStepPostHook, func() error {
+			result := hooks.runPost(context.Background(), hookActionSwitch, &session)
+			tx.report.Hooks = append(tx.report.Hooks, *result)
+			return nil
+		})
 	}
-	fmt.Println("✅ Epic Games Launcher started successfully.")
+	tx.commit()
 	return nil
 }
 
-// isProcessRunning reports whether a process with the given image name is currently running.
-func isProcessRunning(imageName string) bool {
-	checkCmd := helper.NewCommand("tasklist", "/FI", "IMAGENAME eq "+imageName)
-	output, _ := checkCmd.Output()
-	return strings.Contains(string(output), imageName)
-}
+// closeLauncher force-kills the Epic Games Launcher and waits for it to
+// exit, or for ctx to be done. It reports whether the launcher was running.
+func closeLauncher(ctx context.Context, pc helper.ProcessController) (bool, error) {
+	found, err := helper.TerminateAll(pc, launcherProcess)
+	if err != nil {
+		return found > 0, fmt.Errorf("failed to close Epic Games Launcher: %w", err)
+	}
+	if found == 0 {
+		return false, nil
+	}
 
-// waitForProcessExit polls until the named process is no longer running, or the timeout elapses.
-func waitForProcessExit(imageName string, maxWait time.Duration) bool {
-	const pollInterval = 250 * time.Millisecond
-	timeout := time.After(maxWait)
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-timeout:
-			return !isProcessRunning(imageName)
-		case <-ticker.C:
-			if !isProcessRunning(imageName) {
-				return true
-			}
+	if !pc.WaitForExit(ctx, launcherProcess, launcherExitTimeout) {
+		if err := switchCancelled(ctx); err != nil {
+			return true, err
 		}
+		return true, fmt.Errorf("timeout waiting for Epic Games Launcher to close")
 	}
+	return true, nil
 }
 
 // killAuxiliaryProcesses force-kills any leftover launcher helper processes.
-func killAuxiliaryProcesses() {
+func killAuxiliaryProcesses(pc helper.ProcessController) {
 	for _, imageName := range auxiliaryLauncherProcesses {
-		if !isProcessRunning(imageName) {
-			continue
-		}
-		killCmd := helper.NewCommand("taskkill", "/IM", imageName, "/F")
-		if err := killCmd.Run(); err == nil {
+		found, err := helper.TerminateAll(pc, imageName)
+		if err == nil && found > 0 {
 			fmt.Println("✅ Cleaned up leftover process:", imageName)
 		}
 	}
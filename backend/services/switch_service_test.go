@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"epic-games-account-switcher/backend/helper"
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
+	"epic-games-account-switcher/backend/utils"
+)
+
+const (
+	testOldToken = "old-token-0123456789abcdef0123456789"
+	testNewToken = "new-token-0123456789abcdef0123456789"
+)
+
+// errAny matches any error in a test table.
+var errAny = errors.New("any error")
+
+const testSessionIni = "[RememberMe]\nEnable=True\nData=" + testOldToken + "\n\n[Preferences]\nTheme=Dark\n"
+
+// testController wraps a FakeProcessController to fail the first few
+// Start calls and to run a hook when a process is terminated.
+type testController struct {
+	*helper.FakeProcessController
+
+	mu            sync.Mutex
+	startFailures int
+	onTerminate   func(helper.Process)
+}
+
+func (c *testController) Terminate(p helper.Process) error {
+	c.mu.Lock()
+	onTerminate := c.onTerminate
+	c.mu.Unlock()
+
+	if onTerminate != nil {
+		onTerminate(p)
+	}
+	return c.FakeProcessController.Terminate(p)
+}
+
+func (c *testController) Start(path string, args ...string) error {
+	c.mu.Lock()
+	if c.startFailures > 0 {
+		c.startFailures--
+		c.mu.Unlock()
+		return errors.New("start failed")
+	}
+	c.mu.Unlock()
+	return c.FakeProcessController.Start(path, args...)
+}
+
+// switchTestEnv is a switch service running against temporary folders, a
+// fake process list with the launcher running, and one stored account.
+type switchTestEnv struct {
+	service      *SwitchService
+	store        *SessionStore
+	processes    *testController
+	iniPath      string
+	manifestsDir string
+	session      models.LoginSession
+}
+
+func newSwitchTestEnv(t *testing.T) *switchTestEnv {
+	t.Helper()
+
+	root := t.TempDir()
+	for _, key := range []string{"XDG_CACHE_HOME", "LOCALAPPDATA", "HOME", "USERPROFILE", "ProgramData", "ProgramFiles(x86)"} {
+		t.Setenv(key, filepath.Join(root, key))
+	}
+
+	iniPath := utils.GetEpicLoginSessionPath()
+	if err := os.MkdirAll(filepath.Dir(iniPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(iniPath, []byte(testSessionIni), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewSessionStoreWithProtector(NewSettingsService(), security.NoopProtector{})
+	session := models.LoginSession{UserID: "user-b", Username: "PlayerB", LoginToken: testNewToken}
+	if err := store.addOrUpdate(sourceSessionStore, session); err != nil {
+		t.Fatal(err)
+	}
+
+	processes := &testController{FakeProcessController: helper.NewFakeProcessController(launcherProcess, "EpicWebHelper.exe")}
+	return &switchTestEnv{
+		service:      NewSwitchServiceWithController(store, processes),
+		store:        store,
+		processes:    processes,
+		iniPath:      iniPath,
+		manifestsDir: utils.GetEpicManifestsPath(),
+		session:      session,
+	}
+}
+
+// installGame writes a launcher manifest for a game installed under the
+// temporary folders.
+func (e *switchTestEnv) installGame(t *testing.T, appName, displayName, launchExe string) {
+	t.Helper()
+
+	if err := os.MkdirAll(e.manifestsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"AppName": "` + appName + `", "DisplayName": "` + displayName + `", ` +
+		`"InstallLocation": "` + filepath.ToSlash(filepath.Join(filepath.Dir(e.manifestsDir), appName)) + `", ` +
+		`"LaunchExecutable": "` + launchExe + `"}`
+	if err := os.WriteFile(filepath.Join(e.manifestsDir, appName+".item"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeLauncherLog replaces the launcher's current log with lines.
+func (e *switchTestEnv) writeLauncherLog(t *testing.T, lines ...string) {
+	t.Helper()
+
+	logsDir := utils.GetEpicLogsPath()
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(logsDir, launcherLogName), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (e *switchTestEnv) readIni(t *testing.T) string {
+	t.Helper()
+
+	data, err := os.ReadFile(e.iniPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSwitchAccount(t *testing.T) {
+	relaunch := "relaunch Epic Games Launcher on the previous account"
+	restore := "restore GameUserSettings.ini"
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, e *switchTestEnv)
+		// wantErr is nil for a switch that should succeed.
+		wantErr        error
+		wantFailedStep string
+		wantRolledBack []string
+		// wantStarts is how many times the launcher should have been started.
+		wantStarts int
+		// wantToken is the token the session file should end up holding.
+		wantToken string
+	}{
+		{
+			name:       "switches and relaunches",
+			wantStarts: 1,
+			wantToken:  testNewToken,
+		},
+		{
+			name: "kill failure",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.processes.TerminateErr = errors.New("access denied")
+			},
+			wantErr:        errAny,
+			wantFailedStep: SwitchStepCloseLauncher,
+			wantRolledBack: []string{relaunch},
+			wantStarts:     1,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "launcher won't exit",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.processes.Unkillable[launcherProcess] = true
+			},
+			wantErr:        errAny,
+			wantFailedStep: SwitchStepCloseLauncher,
+			wantRolledBack: []string{relaunch},
+			wantStarts:     1,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "start failure rolls back",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.processes.startFailures = 1
+			},
+			wantErr:        errAny,
+			wantFailedStep: SwitchStepRelaunch,
+			wantRolledBack: []string{restore, relaunch},
+			wantStarts:     1,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "cancel before write-session",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.processes.onTerminate = func(p helper.Process) {
+					if strings.EqualFold(p.Name, launcherProcess) {
+						if err := e.service.CancelSwitch(); err != nil {
+							t.Errorf("CancelSwitch: %v", err)
+						}
+					}
+				}
+			},
+			wantErr:        ErrSwitchCancelled,
+			wantFailedStep: SwitchStepKillHelpers,
+			wantRolledBack: []string{relaunch},
+			wantStarts:     1,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "blocked by a running game",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.installGame(t, "Fortnite", "Fortnite", "FortniteGame/Binaries/Win64/FortniteClient-Win64-Shipping.exe")
+				e.processes.Spawn("FortniteClient-Win64-Shipping.exe")
+			},
+			wantErr:        ErrSwitchBlocked,
+			wantFailedStep: SwitchStepPreflight,
+			wantStarts:     0,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "blocked by a download",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.installGame(t, "Sugar", "Rocket League", "Binaries/Win64/RocketLeague.exe")
+				e.writeLauncherLog(t, "[2026.10.18-10.00.00:000][  1]LogInstall: Starting download of Sugar")
+			},
+			wantErr:        ErrSwitchBlocked,
+			wantFailedStep: SwitchStepPreflight,
+			wantStarts:     0,
+			wantToken:      testOldToken,
+		},
+		{
+			name: "not blocked by a finished download or a loose mention",
+			setup: func(t *testing.T, e *switchTestEnv) {
+				e.installGame(t, "Sugar", "Rocket League", "Binaries/Win64/RocketLeague.exe")
+				e.writeLauncherLog(t,
+					"[2026.10.18-10.00.00:000][  1]LogInstall: Starting download of Sugar",
+					"[2026.10.18-10.05.00:000][  2]LogInstall: Download complete for Sugar",
+					"[2026.10.18-10.06.00:000][  3]LogStore: Starting download of SugarFreeDLC, Rocket League offer",
+				)
+			},
+			wantStarts: 1,
+			wantToken:  testNewToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newSwitchTestEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, e)
+			}
+
+			report, err := e.service.switchAccount(context.Background(), e.session, false, false)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("switch failed: %v", err)
+			case tt.wantErr != nil && err == nil:
+				t.Fatalf("switch succeeded, want an error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if report.FailedStep != tt.wantFailedStep {
+				t.Errorf("failed step = %q, want %q", report.FailedStep, tt.wantFailedStep)
+			}
+			if strings.Join(report.RolledBack, "|") != strings.Join(tt.wantRolledBack, "|") {
+				t.Errorf("rolled back = %q, want %q", report.RolledBack, tt.wantRolledBack)
+			}
+			if len(report.RollbackErrors) > 0 {
+				t.Errorf("rollback errors: %q", report.RollbackErrors)
+			}
+			if starts := len(e.processes.Started()); starts != tt.wantStarts {
+				t.Errorf("launcher started %d times, want %d", starts, tt.wantStarts)
+			}
+
+			ini := e.readIni(t)
+			if !strings.Contains(ini, "Data="+tt.wantToken) {
+				t.Errorf("session file doesn't hold the expected token:\n%s", ini)
+			}
+			if !strings.Contains(ini, "[Preferences]\nTheme=Dark") {
+				t.Errorf("session file lost its other settings:\n%s", ini)
+			}
+			if tt.wantToken == testOldToken && ini != testSessionIni {
+				t.Errorf("session file changed:\n%s", ini)
+			}
+
+			sessions, err := e.store.LoadSessions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantCount := 0
+			if tt.wantErr == nil {
+				wantCount = 1
+			}
+			if got := findSession(sessions, e.session.UserID).SwitchCount; got != wantCount {
+				t.Errorf("switch count = %d, want %d", got, wantCount)
+			}
+		})
+	}
+}
+
+func TestSwitchAccountForcedPastBlockers(t *testing.T) {
+	e := newSwitchTestEnv(t)
+	e.installGame(t, "Fortnite", "Fortnite", "FortniteGame/Binaries/Win64/FortniteClient-Win64-Shipping.exe")
+	e.processes.Spawn("FortniteClient-Win64-Shipping.exe")
+
+	report, err := e.service.switchAccount(context.Background(), e.session, false, true)
+	if err != nil {
+		t.Fatalf("forced switch failed: %v", err)
+	}
+	if len(report.Blockers) != 1 || report.Blockers[0].AppName != "Fortnite" {
+		t.Errorf("blockers = %+v, want the running game", report.Blockers)
+	}
+	if !strings.Contains(e.readIni(t), "Data="+testNewToken) {
+		t.Error("forced switch didn't write the new session")
+	}
+}
+
+func TestSwitchAccountBlockedSkipsHooks(t *testing.T) {
+	e := newSwitchTestEnv(t)
+	settings := e.store.settings.GetSettings()
+	settings.PreSwitchHook = "echo pre"
+	settings.PostSwitchHook = "echo post"
+	if err := e.store.settings.UpdateSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	e.installGame(t, "Fortnite", "Fortnite", "FortniteGame/Binaries/Win64/FortniteClient-Win64-Shipping.exe")
+	e.processes.Spawn("FortniteClient-Win64-Shipping.exe")
+
+	report, err := e.service.switchAccount(context.Background(), e.session, false, false)
+	if !errors.Is(err, ErrSwitchBlocked) {
+		t.Fatalf("error = %v, want %v", err, ErrSwitchBlocked)
+	}
+	if len(report.Hooks) != 0 {
+		t.Errorf("blocked switch ran hooks: %+v", report.Hooks)
+	}
+
+	report, err = e.service.switchAccount(context.Background(), e.session, false, true)
+	if err != nil {
+		t.Fatalf("forced switch failed: %v", err)
+	}
+	hooks := []string{}
+	for _, result := range report.Hooks {
+		hooks = append(hooks, result.Hook)
+	}
+	if strings.Join(hooks, ",") != HookPreSwitch+","+HookPostSwitch {
+		t.Errorf("hooks run = %q, want the pre- then the post-switch hook once each", hooks)
+	}
+}
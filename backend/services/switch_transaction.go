@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+// Steps of an account switch, in the order they run.
+const (
+	SwitchStepCloseLauncher = "close-launcher"
+	SwitchStepKillHelpers   = "kill-helpers"
+	SwitchStepWriteSession  = "write-session"
+	SwitchStepRelaunch      = "relaunch"
+)
+
+// SwitchReport describes how a switch went, with how long each step took.
+// On failure it names the step that failed and what was undone to put
+// things back.
+type SwitchReport struct {
+	UserID         string           `json:"userId"`
+	Steps          []string         `json:"steps"`
+	DurationsMs    map[string]int64 `json:"durationsMs"`
+	TotalMs        int64            `json:"totalMs"`
+	FailedStep     string           `json:"failedStep,omitempty"`
+	Cancelled      bool             `json:"cancelled,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	RolledBack     []string         `json:"rolledBack,omitempty"`
+	RollbackErrors []string         `json:"rollbackErrors,omitempty"`
+	// Blockers lists running games and downloads found before the launcher
+	// was closed.
+	Blockers []SwitchBlocker `json:"blockers,omitempty"`
+	// Verification is set when switches are verified (see
+	// AppSettings.VerifySwitch).
+	Verification *SwitchVerification `json:"verification,omitempty"`
+	// Hooks lists the pre- and post-switch hooks that ran.
+	Hooks []HookResult `json:"hooks,omitempty"`
+	// AppName is the game launched after the switch, for SwitchAndLaunch.
+	AppName string `json:"appName,omitempty"`
+}
+
+// SwitchError is returned when a switch fails part way. Its message says
+// what was rolled back, since that is all the frontend gets to see.
+type SwitchError struct {
+	Report *SwitchReport
+	Err    error
+}
+
+func (e *SwitchError) Error() string {
+	msg := e.Err.Error()
+	if len(e.Report.RolledBack) > 0 {
+		msg += "; rollback done: " + strings.Join(e.Report.RolledBack, ", ")
+	}
+	if len(e.Report.RollbackErrors) > 0 {
+		msg += "; rollback failed: " + strings.Join(e.Report.RollbackErrors, ", ")
+	}
+	return msg
+}
+
+func (e *SwitchError) Unwrap() error { return e.Err }
+
+// switchUndo reverts one completed step. The description is phrased as an
+// action, e.g. "restore GameUserSettings.ini".
+type switchUndo struct {
+	description string
+	fn          func() error
+}
+
+// switchTx runs a switch as a sequence of steps, each of which can register
+// how to undo it. On failure the registered undos run newest first. Every
+// step is timed and reported through emit as it starts and ends.
+type switchTx struct {
+	report  *SwitchReport
+	undos   []switchUndo
+	started time.Time
+	emit    func(SwitchStageEvent)
+	// relaunched marks when the launcher was started on the new account.
+	relaunched relaunchMark
+}
+
+func newSwitchTx(userID string, emit func(SwitchStageEvent)) *switchTx {
+	return &switchTx{
+		report:  &SwitchReport{UserID: userID, Steps: []string{}, DurationsMs: map[string]int64{}},
+		started: time.Now(),
+		emit:    emit,
+	}
+}
+
+// run runs one step and records it as done.
+func (t *switchTx) run(step string, fn func() error) error {
+	start := time.Now()
+	t.stage(step, SwitchStageStarted, 0, nil)
+
+	err := fn()
+	elapsed := time.Since(start).Milliseconds()
+	t.report.DurationsMs[step] = elapsed
+	t.report.TotalMs = time.Since(t.started).Milliseconds()
+
+	if err != nil {
+		t.report.FailedStep = step
+		status := SwitchStageFailed
+		if errors.Is(err, ErrSwitchCancelled) {
+			status = SwitchStageCancelled
+		}
+		t.stage(step, status, elapsed, err)
+		return err
+	}
+	t.report.Steps = append(t.report.Steps, step)
+	t.stage(step, SwitchStageDone, elapsed, nil)
+	return nil
+}
+
+// stage reports a step's progress.
+func (t *switchTx) stage(step, status string, elapsedMs int64, err error) {
+	event := SwitchStageEvent{
+		UserID:    t.report.UserID,
+		Stage:     step,
+		Status:    status,
+		ElapsedMs: elapsedMs,
+		TotalMs:   time.Since(t.started).Milliseconds(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	t.emit(event)
+}
+
+// onRollback registers how to undo the step that just ran.
+func (t *switchTx) onRollback(description string, fn func() error) {
+	t.undos = append(t.undos, switchUndo{description: description, fn: fn})
+}
+
+// commit drops the registered undos once the switch is complete.
+func (t *switchTx) commit() {
+	t.undos = nil
+}
+
+// fail rolls back every completed step and returns the report with a
+// SwitchError describing it.
+func (t *switchTx) fail(err error) (*SwitchReport, error) {
+	t.report.Error = err.Error()
+	t.report.Cancelled = errors.Is(err, ErrSwitchCancelled)
+	fmt.Printf("❌ Switch failed at %s: %v\n", t.report.FailedStep, err)
+
+	start := time.Now()
+	if len(t.undos) > 0 {
+		fmt.Println("↩️ Rolling back...")
+		t.stage(SwitchStepRollback, SwitchStageStarted, 0, nil)
+	}
+	for i := len(t.undos) - 1; i >= 0; i-- {
+		undo := t.undos[i]
+		if undoErr := undo.fn(); undoErr != nil {
+			fmt.Printf("⚠️ Rollback step failed (%s): %v\n", undo.description, undoErr)
+			t.report.RollbackErrors = append(t.report.RollbackErrors, fmt.Sprintf("%s (%v)", undo.description, undoErr))
+			continue
+		}
+		fmt.Println("↩️ Rolled back:", undo.description)
+		t.report.RolledBack = append(t.report.RolledBack, undo.description)
+	}
+	if len(t.undos) > 0 {
+		status := SwitchStageDone
+		if len(t.report.RollbackErrors) > 0 {
+			status = SwitchStageFailed
+		}
+		t.stage(SwitchStepRollback, status, time.Since(start).Milliseconds(), nil)
+	}
+	t.undos = nil
+	t.report.TotalMs = time.Since(t.started).Milliseconds()
+
+	return t.report, &SwitchError{Report: t.report, Err: err}
+}
+
+// fileSnapshot holds a file's content from before a switch touched it.
+type fileSnapshot struct {
+	path   string
+	data   []byte
+	exists bool
+}
+
+func snapshotFile(path string) (*fileSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &fileSnapshot{path: path}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s: %w", path, err)
+	}
+	return &fileSnapshot{path: path, data: data, exists: true}, nil
+}
+
+// restore puts the file back as it was, removing it if it didn't exist.
+func (f *fileSnapshot) restore() error {
+	if !f.exists {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return utils.WriteFileAtomic(f.path, f.data, 0644)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+// SwitchStepVerify is the optional step that confirms the launcher signed
+// in as the chosen account.
+const SwitchStepVerify = "verify"
+
+// Results of verifying a switch.
+const (
+	VerifyVerified = "verified"
+	VerifyRejected = "rejected"
+	VerifyUnknown  = "unknown"
+)
+
+// verifyPollInterval is how often the launcher's files are checked while
+// verifying a switch.
+const verifyPollInterval = 500 * time.Millisecond
+
+// launcherLogName is the log the running launcher writes to; older runs are
+// rotated to EpicGamesLauncher-backup-*.log.
+const launcherLogName = "EpicGamesLauncher.log"
+
+// tokenRejectedMarkers appear in the launcher log when it refuses a
+// remember-me token and falls back to the login screen.
+var tokenRejectedMarkers = []string{
+	"errors.com.epicgames.account.oauth.invalid_grant",
+	"invalid_refresh_token",
+	"refresh token is invalid",
+}
+
+// SwitchVerification is the outcome of checking who the launcher signed in
+// as after a switch.
+type SwitchVerification struct {
+	Result       string `json:"result"`
+	ActiveUserID string `json:"activeUserId,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	ElapsedMs    int64  `json:"elapsedMs"`
+
+	// tokenRefused is set when the launcher turned the stored token down,
+	// as opposed to signing in as someone else.
+	tokenRefused bool
+}
+
+// relaunchMark is taken just before the launcher is relaunched: its time,
+// and how far the current log had been written, so that lines from earlier
+// runs aren't read as this run's. The log's first line tells whether the
+// launcher has since started a new log.
+type relaunchMark struct {
+	at        time.Time
+	logOffset int64
+	logHead   string
+}
+
+// switchVerifier watches the launcher's Data folder, current log and
+// session ini for signs of which account it signed in as.
+type switchVerifier struct {
+	dataDir string
+	logsDir string
+	iniPath string
+}
+
+func newSwitchVerifier() *switchVerifier {
+	return &switchVerifier{
+		dataDir: utils.GetEpicDataPath(),
+		logsDir: utils.GetEpicLogsPath(),
+		iniPath: utils.GetEpicLoginSessionPath(),
+	}
+}
+
+// mark records the moment before a relaunch.
+func (v *switchVerifier) mark() relaunchMark {
+	mark := relaunchMark{at: time.Now()}
+	file, err := os.Open(filepath.Join(v.logsDir, launcherLogName))
+	if err != nil {
+		return mark
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil {
+		mark.logOffset = info.Size()
+		mark.logHead = firstLogLine(file)
+	}
+	return mark
+}
+
+// firstLogLine returns the opening line of a launcher log, which names the
+// time that run of the launcher started.
+func firstLogLine(file *os.File) string {
+	buf := make([]byte, 512)
+	n, _ := file.ReadAt(buf, 0)
+	head := buf[:n]
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	return string(head)
+}
+
+// verify polls until the launcher, relaunched at since, shows userID as
+// signed in, shows the token was refused, or timeout passes or ctx is done.
+func (v *switchVerifier) verify(ctx context.Context, userID string, since relaunchMark, timeout time.Duration) *SwitchVerification {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(verifyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if result := v.check(userID, since); result != nil {
+			result.ElapsedMs = time.Since(start).Milliseconds()
+			return result
+		}
+		select {
+		case <-ctx.Done():
+			return &SwitchVerification{
+				Result:    VerifyUnknown,
+				Reason:    fmt.Sprintf("no sign-in seen within %s", time.Since(start).Round(time.Second)),
+				ElapsedMs: time.Since(start).Milliseconds(),
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// check looks once for a definite answer, returning nil if there's none yet.
+func (v *switchVerifier) check(userID string, since relaunchMark) *SwitchVerification {
+	// 1️⃣ The launcher writes a file named after the account it signs in as
+	if active, modTime, err := newestDataFileUser(v.dataDir); err == nil && modTime.After(since.at) {
+		if strings.EqualFold(active, userID) {
+			return &SwitchVerification{Result: VerifyVerified, ActiveUserID: active}
+		}
+		return &SwitchVerification{
+			Result:       VerifyRejected,
+			ActiveUserID: active,
+			Reason:       "the launcher signed in as a different account",
+		}
+	}
+
+	// 2️⃣ A refused token is logged, and the launcher shows its login screen
+	if marker := v.rejectionLogged(since); marker != "" {
+		return &SwitchVerification{Result: VerifyRejected, Reason: "the launcher refused the saved login (" + marker + ")", tokenRefused: true}
+	}
+
+	// 3️⃣ It also clears the remember-me token it couldn't use
+	if info, err := os.Stat(v.iniPath); err == nil && info.ModTime().After(since.at) {
+		if data, err := os.ReadFile(v.iniPath); err == nil && rememberMeToken(string(data)) == "" {
+			return &SwitchVerification{Result: VerifyRejected, Reason: "the launcher cleared the saved login", tokenRefused: true}
+		}
+	}
+	return nil
+}
+
+// rejectionLogged returns the first token-rejected marker in the launcher
+// log written since the relaunch, or "" if there is none. Only the part of
+// the log past the mark is read, unless the launcher has started a new log.
+func (v *switchVerifier) rejectionLogged(since relaunchMark) string {
+	path := filepath.Join(v.logsDir, launcherLogName)
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().After(since.at) {
+		return ""
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	offset := since.logOffset
+	if info.Size() < offset || firstLogLine(file) != since.logHead {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.ToLower(scanner.Text())
+		for _, marker := range tokenRejectedMarkers {
+			if strings.Contains(line, marker) {
+				return marker
+			}
+		}
+	}
+	return ""
+}
+
+// verifyLogin waits for the relaunched launcher to sign in and records the
+// outcome on the session: a refused token flags it as needing a fresh
+// login, a confirmed one clears that flag.
+func (s *SwitchService) verifyLogin(ctx context.Context, userID string, relaunched relaunchMark, timeout time.Duration) *SwitchVerification {
+	fmt.Println("🔎 Verifying the launcher signs in as:", userID)
+	result := s.verifier.verify(ctx, userID, relaunched, timeout)
+
+	switch {
+	case result.Result == VerifyVerified:
+		fmt.Printf("✅ Switch verified in %dms\n", result.ElapsedMs)
+		if err := s.sessionStore.setNeedsRelogin(sourceSwitch, userID, false); err != nil {
+			fmt.Printf("⚠️ Failed to update session: %v\n", err)
+		}
+	case result.tokenRefused:
+		fmt.Println("❌ Switch rejected:", result.Reason)
+		if err := s.sessionStore.setNeedsRelogin(sourceSwitch, userID, true); err != nil {
+			fmt.Printf("⚠️ Failed to flag session for re-login: %v\n", err)
+		}
+	default:
+		fmt.Printf("⚠️ Switch not verified (%s): %s\n", result.Result, result.Reason)
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"epic-games-account-switcher/backend/utils"
+)
+
+// appendLauncherLog adds lines to the launcher's current log and dates it
+// after mark, as the relaunched launcher would.
+func appendLauncherLog(t *testing.T, mark relaunchMark, lines ...string) {
+	t.Helper()
+
+	path := filepath.Join(utils.GetEpicLogsPath(), launcherLogName)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range lines {
+		if _, err := file.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	later := mark.at.Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRejectionLoggedSinceRelaunch(t *testing.T) {
+	const refused = "[2026.10.18-09.00.00:000][  1]LogEOSAuth: Error: errors.com.epicgames.account.oauth.invalid_grant"
+	const progress = "[2026.10.18-09.00.01:000][  2]LogHttp: Request complete"
+
+	e := newSwitchTestEnv(t)
+	e.writeLauncherLog(t, "Log file open, 10/18/26 09:00:00", refused)
+	verifier := e.service.verifier
+	mark := verifier.mark()
+
+	appendLauncherLog(t, mark, progress)
+	if marker := verifier.rejectionLogged(mark); marker != "" {
+		t.Errorf("a refusal from before the relaunch was reported: %q", marker)
+	}
+
+	appendLauncherLog(t, mark, refused)
+	if marker := verifier.rejectionLogged(mark); marker == "" {
+		t.Error("a refusal after the relaunch wasn't reported")
+	}
+
+	// A new log is read from the start, even once it outgrows the old one
+	e.writeLauncherLog(t, "Log file open, 10/18/26 10:00:00", refused, progress, progress)
+	appendLauncherLog(t, mark)
+	if marker := verifier.rejectionLogged(mark); marker == "" {
+		t.Error("a refusal in a rotated log wasn't reported")
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// minLoginTokenLength is the shortest Data= value treated as a real
+// remember-me token; the launcher leaves shorter values when logged out.
+const minLoginTokenLength = 1000
+
+var loginTokenPattern = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+
+// TokenImportRequest describes an account to import from a launcher
+// GameUserSettings.ini or a pasted token. UserID and Username are optional;
+// when empty they're resolved from the files next to the ini (its Data
+// folder and logs), this machine's launcher logs or the stored accounts.
+type TokenImportRequest struct {
+	IniPath  string `json:"iniPath"`
+	Token    string `json:"token"`
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	Alias    string `json:"alias"`
+}
+
+// rememberMePayload is the readable form some launcher versions store in
+// Data=. Newer versions encrypt it, in which case nothing is decoded.
+type rememberMePayload struct {
+	DisplayName string `json:"DisplayName"`
+}
+
+// SelectSettingsFile opens a file dialog for choosing a GameUserSettings.ini.
+func (t *TransferService) SelectSettingsFile() (string, error) {
+	if t.ctx == nil {
+		return "", fmt.Errorf("context not set for TransferService")
+	}
+
+	return runtime.OpenFileDialog(t.ctx, runtime.OpenDialogOptions{
+		Title: "Import Account From Launcher Settings",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Launcher settings (*.ini)", Pattern: "*.ini"},
+		},
+	})
+}
+
+// ImportToken validates the token from req, resolves the account it belongs
+// to and stores it like a detected login. The returned session has its
+// token omitted.
+func (t *TransferService) ImportToken(req TokenImportRequest) (*models.LoginSession, error) {
+	// 1️⃣ Get the token, from the ini's [RememberMe] section or pasted text
+	var token string
+	var dataDir, logsDir string
+	if req.IniPath != "" {
+		data, err := os.ReadFile(req.IniPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read settings file: %w", err)
+		}
+		if token = rememberMeToken(string(data)); token == "" {
+			return nil, fmt.Errorf("no [RememberMe] token found in %s", filepath.Base(req.IniPath))
+		}
+
+		// <root>/Saved/Config/<platform>/GameUserSettings.ini → <root>/Saved
+		saved := filepath.Dir(filepath.Dir(filepath.Dir(req.IniPath)))
+		dataDir = filepath.Join(saved, "Data")
+		logsDir = filepath.Join(saved, "Logs")
+	} else {
+		token = pastedToken(req.Token)
+	}
+
+	// 2️⃣ Validate it
+	if err := validateLoginToken(token); err != nil {
+		return nil, err
+	}
+
+	// 3️⃣ Resolve who it belongs to
+	session := models.LoginSession{
+		UserID:     strings.TrimSpace(req.UserID),
+		Username:   strings.TrimSpace(req.Username),
+		Alias:      strings.TrimSpace(req.Alias),
+		LoginToken: token,
+	}
+	if session.Username == "" {
+		session.Username = decodeTokenDisplayName(token)
+	}
+	stored, err := t.sessionStore.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveTokenAccount(&session, stored, dataDir, logsDir); err != nil {
+		return nil, err
+	}
+
+	// 4️⃣ Store it the same way as a detected login, replacing the token of
+	// an account that's already known
+	if err := t.sessionStore.importToken(sourceTransfer, session); err != nil {
+		return nil, fmt.Errorf("failed to persist session: %w", err)
+	}
+
+	fmt.Println("✅ Imported session from token:", session.UserID)
+	session.LoginToken = ""
+	return &session, nil
+}
+
+// rememberMeToken returns the Data= value of the [RememberMe] section in ini
+// content, or "" if there is none.
+func rememberMeToken(content string) string {
+	inSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			inSection = strings.EqualFold(trimmed, rememberMeSection)
+			continue
+		}
+		if inSection && strings.HasPrefix(trimmed, "Data=") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, "Data="))
+		}
+	}
+	return ""
+}
+
+// pastedToken accepts a bare token, a Data= line or a whole [RememberMe]
+// section and returns just the token.
+func pastedToken(text string) string {
+	if token := rememberMeToken(text); token != "" {
+		return token
+	}
+	text = strings.TrimSpace(text)
+	if idx := strings.Index(text, "Data="); idx != -1 {
+		text = text[idx+len("Data="):]
+		if end := strings.IndexAny(text, "\r\n"); end != -1 {
+			text = text[:end]
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// validateLoginToken rejects values that can't be a remember-me token.
+func validateLoginToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("no token found")
+	}
+	if len(token) < minLoginTokenLength {
+		return fmt.Errorf("token is too short to be a login token (the launcher was likely logged out)")
+	}
+	if !loginTokenPattern.MatchString(token) {
+		return fmt.Errorf("token contains unexpected characters")
+	}
+	return nil
+}
+
+// decodeTokenDisplayName returns the display name stored in a readable
+// token, or "" if the token is encrypted or not in the expected form.
+func decodeTokenDisplayName(token string) string {
+	data, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return ""
+	}
+
+	var entries []rememberMePayload
+	if err := json.Unmarshal(data, &entries); err != nil || len(entries) == 0 {
+		return ""
+	}
+	return entries[0].DisplayName
+}
+
+// resolveTokenAccount fills in the session's UserID and Username. The
+// supplied Data folder and logs (next to an imported ini) are tried first,
+// then this machine's launcher logs, then the accounts already stored.
+func resolveTokenAccount(session *models.LoginSession, stored []models.LoginSession, dataDir string, logsDir string) error {
+	if session.UserID == "" && dataDir != "" {
+		if userID, err := userIDFromDataFolder(dataDir); err == nil {
+			session.UserID = userID
+		}
+	}
+
+	// Newer logs win; within a log, the last launch line does
+	accounts := map[string]string{}
+	for _, dir := range []string{logsDir, utils.GetEpicLogsPath()} {
+		if dir == "" {
+			continue
+		}
+		logFiles, _ := filepath.Glob(filepath.Join(dir, "*EpicGamesLauncher*.log"))
+		for _, path := range newestFirst(logFiles) {
+			for userID, username := range readLaunchedAccounts([]string{path}) {
+				if _, seen := accounts[userID]; !seen {
+					accounts[userID] = username
+				}
+			}
+		}
+	}
+
+	for _, sess := range stored {
+		if _, seen := accounts[sess.UserID]; !seen && sess.Username != "" {
+			accounts[sess.UserID] = sess.Username
+		}
+	}
+
+	// A known display name can point at the user ID through the logs, as
+	// long as only one account uses it
+	if session.UserID == "" && session.Username != "" {
+		matches := []string{}
+		for userID, username := range accounts {
+			if strings.EqualFold(username, session.Username) {
+				matches = append(matches, userID)
+			}
+		}
+		if len(matches) > 1 {
+			return fmt.Errorf("several accounts are named %s, enter the user ID of this one", session.Username)
+		}
+		if len(matches) == 1 {
+			session.UserID = matches[0]
+		}
+	}
+	if session.UserID == "" {
+		return fmt.Errorf("could not determine which account this token belongs to, enter its user ID")
+	}
+
+	if session.Username == "" {
+		session.Username = accounts[session.UserID]
+	}
+	return nil
+}
+
+// newestFirst returns the files at paths sorted by modification time, newest
+// first. Files that can't be read, e.g. a log rotated away since it was
+// listed, are left out.
+func newestFirst(paths []string) []string {
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	files := []file{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		files = append(files, file{path, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	sorted := make([]string, len(files))
+	for i, f := range files {
+		sorted[i] = f.path
+	}
+	return sorted
+}
+
+// importToken stores an imported account in one update: a new account is
+// added like a detected login, and a known one gets the token (clearing any
+// re-login flag), plus the username and alias where given.
+func (s *SessionStore) importToken(source string, session models.LoginSession) error {
+	return s.update(mutation{"import-token", source}, func(file *models.SessionFile) error {
+		now := time.Now().Format(time.RFC3339)
+		existing := findSession(file.Sessions, session.UserID)
+		if existing == nil {
+			session.CreatedAt = now
+			session.UpdatedAt = now
+			session.SortOrder = nextSortOrder(file.Sessions)
+			file.Sessions = append(file.Sessions, session)
+			return nil
+		}
+
+		if existing.Username == "" && session.Username != "" {
+			existing.Username = session.Username
+		}
+		if session.Alias != "" {
+			existing.Alias = session.Alias
+		}
+		if existing.TokenFingerprint != tokenFingerprint(session.LoginToken) {
+			existing.LoginToken = session.LoginToken
+			existing.NeedsRelogin = false
+		}
+		existing.UpdatedAt = now
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"epic-games-account-switcher/backend/models"
+)
+
+func TestResolveTokenAccountByName(t *testing.T) {
+	newSwitchTestEnv(t) // Point the launcher logs at an empty folder
+
+	stored := []models.LoginSession{
+		{UserID: "aaa", Username: "Player"},
+		{UserID: "bbb", Username: "Other"},
+	}
+	session := models.LoginSession{Username: "player"}
+	if err := resolveTokenAccount(&session, stored, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if session.UserID != "aaa" {
+		t.Errorf("user ID = %q, want aaa", session.UserID)
+	}
+
+	stored = append(stored, models.LoginSession{UserID: "ccc", Username: "PLAYER"})
+	session = models.LoginSession{Username: "Player"}
+	if err := resolveTokenAccount(&session, stored, "", ""); err == nil || !strings.Contains(err.Error(), "several accounts") {
+		t.Errorf("error = %v, want an ambiguous name error", err)
+	}
+}
+
+func TestImportTokenForKnownAccount(t *testing.T) {
+	e := newSwitchTestEnv(t)
+	if err := e.store.setNeedsRelogin(sourceSessionStore, e.session.UserID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := models.LoginSession{UserID: e.session.UserID, Username: "Renamed", Alias: "Main", LoginToken: testOldToken}
+	if err := e.store.importToken(sourceTransfer, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := e.store.LoadSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := findSession(sessions, e.session.UserID)
+	if sess.Username != "PlayerB" || sess.Alias != "Main" || sess.NeedsRelogin {
+		t.Errorf("session = %+v, want the stored username, the new alias and no re-login flag", sess)
+	}
+	if token, err := e.store.revealLoginToken(e.session.UserID); err != nil || token != testOldToken {
+		t.Errorf("token = %q (%v), want the imported one", token, err)
+	}
+}
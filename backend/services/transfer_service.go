@@ -0,0 +1,524 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"epic-games-account-switcher/backend/models"
+	"epic-games-account-switcher/backend/security"
+	"epic-games-account-switcher/backend/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// bundleMagic starts every export bundle, followed by the salt and the
+// AES-GCM encrypted zip archive.
+const bundleMagic = "EPICSWITCHER-BUNDLE-1\n"
+
+// bundleFormatVersion is the layout of the zip inside a bundle.
+const bundleFormatVersion = 1
+
+// bundleExtension is the file extension offered in the save/open dialogs.
+const bundleExtension = ".esbundle"
+
+// Merge strategies for ImportBundle, applied to accounts that already exist.
+const (
+	MergeSkip      = "skip"
+	MergeOverwrite = "overwrite"
+	MergeNewest    = "newest"
+)
+
+// TransferService exports accounts (with their avatars) to a single
+// passphrase-encrypted bundle and imports them on another machine.
+type TransferService struct {
+	ctx          context.Context
+	sessionStore *SessionStore
+}
+
+// BundleManifest describes a bundle's contents. It's stored inside the
+// encrypted archive, so reading it requires the passphrase.
+type BundleManifest struct {
+	FormatVersion int            `json:"formatVersion"`
+	AppVersion    string         `json:"appVersion"`
+	CreatedAt     string         `json:"createdAt"`
+	SessionCount  int            `json:"sessionCount"`
+	Avatars       []BundleAvatar `json:"avatars"`
+}
+
+// BundleAvatar is one avatar file packed in a bundle.
+type BundleAvatar struct {
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+// ImportConflict is an incoming account whose UserID is already stored.
+type ImportConflict struct {
+	UserID            string `json:"userId"`
+	ExistingName      string `json:"existingName"`
+	IncomingName      string `json:"incomingName"`
+	ExistingUpdatedAt string `json:"existingUpdatedAt"`
+	IncomingUpdatedAt string `json:"incomingUpdatedAt"`
+	IncomingIsNewer   bool   `json:"incomingIsNewer"`
+}
+
+// ImportPreview lists what an import would do, before anything is written.
+type ImportPreview struct {
+	Manifest  BundleManifest   `json:"manifest"`
+	New       []string         `json:"new"`
+	Conflicts []ImportConflict `json:"conflicts"`
+}
+
+// ImportResult reports what an import did, by UserID.
+type ImportResult struct {
+	Added       []string `json:"added"`
+	Overwritten []string `json:"overwritten"`
+	Skipped     []string `json:"skipped"`
+	Avatars     int      `json:"avatars"`
+}
+
+// bundle is the decrypted content of a bundle file.
+type bundle struct {
+	manifest BundleManifest
+	sessions []models.LoginSession
+	avatars  map[string][]byte
+}
+
+// NewTransferService creates a new TransferService backed by the shared session store.
+func NewTransferService(sessionStore *SessionStore) *TransferService {
+	return &TransferService{sessionStore: sessionStore}
+}
+
+// setContext sets the context for the service (unexported to hide from Wails bindings).
+func (t *TransferService) setContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
+// SetTransferServiceContext provides a way for other packages to set the context without exposing it to the frontend bindings.
+func SetTransferServiceContext(t *TransferService, ctx context.Context) {
+	t.setContext(ctx)
+}
+
+// ExportBundle asks where to save, then writes every account and its avatars
+// to an encrypted bundle there. It returns the path, or "" if cancelled.
+func (t *TransferService) ExportBundle(passphrase string) (string, error) {
+	if t.ctx == nil {
+		return "", fmt.Errorf("context not set for TransferService")
+	}
+
+	path, err := runtime.SaveFileDialog(t.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Accounts",
+		DefaultFilename: "epic-switcher-" + time.Now().Format("2006-01-02") + bundleExtension,
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Epic Switcher bundle (*" + bundleExtension + ")", Pattern: "*" + bundleExtension},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", nil // User cancelled
+	}
+
+	if err := t.ExportBundleTo(path, passphrase); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// SelectBundleFile opens a file dialog and returns the chosen bundle path.
+func (t *TransferService) SelectBundleFile() (string, error) {
+	if t.ctx == nil {
+		return "", fmt.Errorf("context not set for TransferService")
+	}
+
+	return runtime.OpenFileDialog(t.ctx, runtime.OpenDialogOptions{
+		Title: "Import Accounts",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Epic Switcher bundle (*" + bundleExtension + ")", Pattern: "*" + bundleExtension},
+		},
+	})
+}
+
+// ExportBundleTo writes every account and the avatar files they reference
+// to an encrypted bundle at path.
+func (t *TransferService) ExportBundleTo(path string, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase is required")
+	}
+
+	// Tokens leave the store decrypted here; they're re-encrypted with the
+	// bundle passphrase below, since the local protector is machine-bound.
+	sessions, err := t.sessionStore.exportSessions()
+	if err != nil {
+		return fmt.Errorf("failed to read sessions: %w", err)
+	}
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+
+	manifest := BundleManifest{
+		FormatVersion: bundleFormatVersion,
+		AppVersion:    utils.AppVersion,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+		SessionCount:  len(sessions),
+		Avatars:       []BundleAvatar{},
+	}
+
+	avatarDir := t.sessionStore.GetAvatarDir()
+	for _, filename := range referencedAvatarFiles(sessions) {
+		data, err := os.ReadFile(filepath.Join(avatarDir, filename))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Referenced but missing; the session keeps its color
+			}
+			return fmt.Errorf("failed to read avatar %s: %w", filename, err)
+		}
+		if err := writeZipFile(zw, "avatars/"+filename, data); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		manifest.Avatars = append(manifest.Avatars, BundleAvatar{Filename: filename, SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	sessionData, err := json.MarshalIndent(models.SessionFile{
+		SchemaVersion: currentSchemaVersion,
+		AppVersion:    utils.AppVersion,
+		Sessions:      sessions,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "login_sessions.json", sessionData); err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "manifest.json", manifestData); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	salt, err := security.NewSalt()
+	if err != nil {
+		return err
+	}
+	protector, err := security.NewPassphraseProtector("bundle", passphrase, salt)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := protector.Protect(archive.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	out := append([]byte(bundleMagic), salt...)
+	out = append(out, ciphertext...)
+	if err := utils.WriteFileAtomic(path, out, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("📦 Exported %d account(s) and %d avatar(s) to %s\n", len(sessions), len(manifest.Avatars), path)
+	return nil
+}
+
+// PreviewImport decrypts a bundle and reports which accounts are new and
+// which conflict (by UserID) with stored ones, without changing anything.
+func (t *TransferService) PreviewImport(path string, passphrase string) (*ImportPreview, error) {
+	b, err := readBundle(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := t.sessionStore.LoadSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ImportPreview{Manifest: b.manifest, New: []string{}, Conflicts: []ImportConflict{}}
+	for _, incoming := range b.sessions {
+		current := findSession(existing, incoming.UserID)
+		if current == nil {
+			preview.New = append(preview.New, incoming.UserID)
+			continue
+		}
+		preview.Conflicts = append(preview.Conflicts, ImportConflict{
+			UserID:            incoming.UserID,
+			ExistingName:      displayName(*current),
+			IncomingName:      displayName(incoming),
+			ExistingUpdatedAt: current.UpdatedAt,
+			IncomingUpdatedAt: incoming.UpdatedAt,
+			IncomingIsNewer:   isNewer(incoming, *current),
+		})
+	}
+	return preview, nil
+}
+
+// ImportBundle merges a bundle's accounts into the store. strategy decides
+// what happens to accounts that already exist: MergeSkip keeps the stored
+// one, MergeOverwrite takes the bundle's, MergeNewest keeps whichever has
+// the later UpdatedAt. Avatar files are copied first so merged sessions
+// never point at a missing image, and removed again if the import fails.
+func (t *TransferService) ImportBundle(path string, passphrase string, strategy string) (*ImportResult, error) {
+	switch strategy {
+	case MergeSkip, MergeOverwrite, MergeNewest:
+	default:
+		return nil, fmt.Errorf("unknown merge strategy: %s", strategy)
+	}
+
+	b, err := readBundle(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	avatarDir := t.sessionStore.GetAvatarDir()
+	if err := os.MkdirAll(avatarDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create avatar directory: %w", err)
+	}
+
+	written := []string{}
+	removeWritten := func() {
+		for _, dest := range written {
+			if err := os.Remove(dest); err != nil && !os.IsNotExist(err) {
+				fmt.Printf("⚠️ Failed to remove imported avatar %s: %v\n", filepath.Base(dest), err)
+			}
+		}
+	}
+	for filename, data := range b.avatars {
+		dest := filepath.Join(avatarDir, filename)
+		if _, err := os.Stat(dest); err == nil {
+			continue // Same name means same content hash; already have it
+		}
+		if err := utils.WriteFileAtomic(dest, data, 0644); err != nil {
+			removeWritten()
+			return nil, fmt.Errorf("failed to save avatar %s: %w", filename, err)
+		}
+		written = append(written, dest)
+	}
+
+	result, err := t.sessionStore.importSessions(sourceTransfer, b.sessions, strategy)
+	if err != nil {
+		removeWritten()
+		return nil, err
+	}
+	result.Avatars = len(written)
+
+	fmt.Printf("📥 Imported bundle: %d added, %d overwritten, %d skipped\n", len(result.Added), len(result.Overwritten), len(result.Skipped))
+	return result, nil
+}
+
+// exportSessions returns every session with its token decrypted, for
+// re-encryption into an export bundle.
+func (s *SessionStore) exportSessions() ([]models.LoginSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := cloneSessions(file.Sessions)
+	sortSessions(sessions)
+	for i := range sessions {
+		if sessions[i].LoginToken, err = security.Unseal(s.protector, sessions[i].LoginToken); err != nil {
+			return nil, fmt.Errorf("failed to decrypt token for %s: %w", sessions[i].UserID, err)
+		}
+	}
+	return sessions, nil
+}
+
+// importSessions merges incoming sessions (with plaintext tokens) into the
+// store in one save, resolving UserID conflicts with strategy.
+func (s *SessionStore) importSessions(source string, incoming []models.LoginSession, strategy string) (*ImportResult, error) {
+	result := &ImportResult{Added: []string{}, Overwritten: []string{}, Skipped: []string{}}
+
+	err := s.update(mutation{"import", source}, func(file *models.SessionFile) error {
+		for _, sess := range incoming {
+			if sess.UserID == "" {
+				continue
+			}
+			sess.TokenFingerprint = ""
+
+			existing := findSession(file.Sessions, sess.UserID)
+			if existing == nil {
+				sess.SortOrder = nextSortOrder(file.Sessions)
+				file.Sessions = append(file.Sessions, sess)
+				result.Added = append(result.Added, sess.UserID)
+				continue
+			}
+
+			if strategy == MergeSkip || (strategy == MergeNewest && !isNewer(sess, *existing)) {
+				result.Skipped = append(result.Skipped, sess.UserID)
+				continue
+			}
+
+			// Keep local placement and switch count, and whichever last use
+			// is more recent; take everything else from the bundle
+			sess.SortOrder = existing.SortOrder
+			sess.Pinned = existing.Pinned
+			sess.SwitchCount = existing.SwitchCount
+			if lastUsed(*existing).After(lastUsed(sess)) {
+				sess.LastUsedAt = existing.LastUsedAt
+			}
+			*existing = sess
+			result.Overwritten = append(result.Overwritten, sess.UserID)
+		}
+
+		if len(result.Added) == 0 && len(result.Overwritten) == 0 {
+			return errNoChanges
+		}
+		return nil
+	})
+	return result, err
+}
+
+// readBundle decrypts and unpacks the bundle at path.
+func readBundle(path string, passphrase string) (*bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if !bytes.HasPrefix(data, []byte(bundleMagic)) || len(data) < len(bundleMagic)+security.SaltSize {
+		return nil, fmt.Errorf("not an Epic Switcher bundle")
+	}
+
+	data = data[len(bundleMagic):]
+	salt, ciphertext := data[:security.SaltSize], data[security.SaltSize:]
+
+	protector, err := security.NewPassphraseProtector("bundle", passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := protector.Unprotect(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or damaged bundle")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("damaged bundle: %w", err)
+	}
+
+	b := &bundle{avatars: map[string][]byte{}}
+	var sessionData, manifestData []byte
+	for _, f := range zr.File {
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("damaged bundle: %w", err)
+		}
+		switch {
+		case f.Name == "manifest.json":
+			manifestData = content
+		case f.Name == "login_sessions.json":
+			sessionData = content
+		case strings.HasPrefix(f.Name, "avatars/"):
+			// Only keep plain file names; never write outside the avatar dir
+			name := strings.TrimPrefix(f.Name, "avatars/")
+			if name != "" && name == filepath.Base(name) && !strings.ContainsAny(name, `/\`) {
+				b.avatars[name] = content
+			}
+		}
+	}
+
+	if manifestData == nil || sessionData == nil {
+		return nil, fmt.Errorf("damaged bundle: missing manifest or sessions")
+	}
+	if err := json.Unmarshal(manifestData, &b.manifest); err != nil {
+		return nil, fmt.Errorf("damaged bundle manifest: %w", err)
+	}
+	if b.manifest.FormatVersion > bundleFormatVersion {
+		return nil, fmt.Errorf("bundle was made by a newer version of Epic Switcher")
+	}
+
+	file, _, err := parseSessionFile(sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("damaged bundle sessions: %w", err)
+	}
+	b.sessions = file.Sessions
+
+	// Drop avatars whose content doesn't match the manifest
+	for _, avatar := range b.manifest.Avatars {
+		content, ok := b.avatars[avatar.Filename]
+		if !ok {
+			continue
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != avatar.SHA256 {
+			fmt.Printf("⚠️ Skipping avatar with bad checksum: %s\n", avatar.Filename)
+			delete(b.avatars, avatar.Filename)
+		}
+	}
+
+	return b, nil
+}
+
+// referencedAvatarFiles returns the avatar files (originals and thumbnails)
+// used by sessions, without duplicates.
+func referencedAvatarFiles(sessions []models.LoginSession) []string {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, sess := range sessions {
+		if sess.AvatarImage == "" {
+			continue
+		}
+		for _, name := range []string{sess.AvatarImage, getThumbnailFilename(sess.AvatarImage)} {
+			if !seen[name] {
+				seen[name] = true
+				files = append(files, name)
+			}
+		}
+	}
+	return files
+}
+
+// displayName returns the friendliest name available for a session.
+func displayName(sess models.LoginSession) string {
+	switch {
+	case sess.Alias != "":
+		return sess.Alias
+	case sess.Username != "":
+		return sess.Username
+	default:
+		return sess.UserID
+	}
+}
+
+// isNewer reports whether a was updated after b. Unparseable dates count as oldest.
+func isNewer(a, b models.LoginSession) bool {
+	ta, _ := time.Parse(time.RFC3339, a.UpdatedAt)
+	tb, _ := time.Parse(time.RFC3339, b.UpdatedAt)
+	return ta.After(tb)
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
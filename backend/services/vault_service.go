@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"epic-games-account-switcher/backend/utils"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultVaultID is the vault that lives directly in the app data folder,
+// where sessions were stored before vaults existed.
+const defaultVaultID = "default"
+
+// maxVaultNameLength caps vault names so they fit in the vault picker.
+const maxVaultNameLength = 40
+
+// EventVaultChanged is emitted after the active vault changes, so the
+// frontend reloads its sessions.
+const EventVaultChanged = "vault:changed"
+
+// Vault is a named, separate set of accounts with its own sessions file and
+// avatar folder.
+type Vault struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	Active    bool   `json:"active"`
+}
+
+// vaultRegistry is persisted in vaults.json. The default vault is always
+// present, even when the file doesn't exist yet.
+type vaultRegistry struct {
+	ActiveVault string  `json:"activeVault"`
+	Vaults      []Vault `json:"vaults"`
+}
+
+// VaultService manages vaults and points the shared session store at the
+// active one.
+type VaultService struct {
+	ctx          context.Context
+	sessionStore *SessionStore
+	registryPath string
+
+	mu       sync.Mutex
+	registry vaultRegistry
+}
+
+// NewVaultService loads the vault list and opens the active vault in the store.
+func NewVaultService(sessionStore *SessionStore) *VaultService {
+	v := &VaultService{
+		sessionStore: sessionStore,
+		registryPath: filepath.Join(utils.GetAppDataPath(), "vaults.json"),
+	}
+
+	if err := v.loadRegistry(); err != nil {
+		fmt.Printf("⚠️ Failed to read vault list: %v\n", err)
+	}
+	if findVault(v.registry.Vaults, v.registry.ActiveVault) == nil {
+		v.registry.ActiveVault = defaultVaultID
+	}
+	if v.registry.ActiveVault != defaultVaultID {
+		v.sessionStore.open(vaultSessionPath(v.registry.ActiveVault))
+	}
+
+	return v
+}
+
+// setContext sets the context for the service (unexported to hide from Wails bindings).
+func (v *VaultService) setContext(ctx context.Context) {
+	v.ctx = ctx
+}
+
+// SetVaultServiceContext provides a way for other packages to set the context without exposing it to the frontend bindings.
+func SetVaultServiceContext(v *VaultService, ctx context.Context) {
+	v.setContext(ctx)
+}
+
+// ListVaults returns every vault, the default one first.
+func (v *VaultService) ListVaults() []Vault {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	vaults := make([]Vault, len(v.registry.Vaults))
+	for i, vault := range v.registry.Vaults {
+		vault.Active = vault.ID == v.registry.ActiveVault
+		vaults[i] = vault
+	}
+	return vaults
+}
+
+// GetActiveVault returns the vault whose accounts are currently shown.
+func (v *VaultService) GetActiveVault() Vault {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	vault := *findVault(v.registry.Vaults, v.registry.ActiveVault)
+	vault.Active = true
+	return vault
+}
+
+// CreateVault adds an empty vault. It doesn't switch to it.
+func (v *VaultService) CreateVault(name string) (*Vault, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	name, err := v.validateName(name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newVaultID()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(vaultDir(id), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create vault folder: %w", err)
+	}
+
+	vault := Vault{ID: id, Name: name, CreatedAt: time.Now().Format(time.RFC3339)}
+	registry := v.registry
+	registry.Vaults = append(append([]Vault{}, v.registry.Vaults...), vault)
+	if err := v.saveRegistry(registry); err != nil {
+		os.RemoveAll(vaultDir(id))
+		return nil, err
+	}
+
+	fmt.Println("🗄️ Created vault:", name)
+	return &vault, nil
+}
+
+// RenameVault changes a vault's display name.
+func (v *VaultService) RenameVault(id string, name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if findVault(v.registry.Vaults, id) == nil {
+		return fmt.Errorf("vault not found")
+	}
+	name, err := v.validateName(name, id)
+	if err != nil {
+		return err
+	}
+
+	registry := v.registry
+	registry.Vaults = append([]Vault{}, v.registry.Vaults...)
+	findVault(registry.Vaults, id).Name = name
+	return v.saveRegistry(registry)
+}
+
+// DeleteVault permanently removes a vault with all its accounts and
+// avatars. The default vault and the active vault can't be deleted.
+func (v *VaultService) DeleteVault(id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if id == defaultVaultID {
+		return fmt.Errorf("the default vault can't be deleted")
+	}
+	if id == v.registry.ActiveVault {
+		return fmt.Errorf("switch to another vault before deleting this one")
+	}
+	vault := findVault(v.registry.Vaults, id)
+	if vault == nil {
+		return fmt.Errorf("vault not found")
+	}
+
+	// Refuse if another window has it open
+	lock, err := utils.TryLockFile(vaultSessionPath(id) + ".lock")
+	if err != nil {
+		return fmt.Errorf("vault is in use by another Epic Switcher window")
+	}
+	lock.Unlock()
+
+	registry := v.registry
+	registry.Vaults = []Vault{}
+	for _, other := range v.registry.Vaults {
+		if other.ID != id {
+			registry.Vaults = append(registry.Vaults, other)
+		}
+	}
+	if err := v.saveRegistry(registry); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(vaultDir(id)); err != nil {
+		fmt.Printf("⚠️ Failed to remove vault folder: %v\n", err)
+	}
+	fmt.Println("🗑️ Deleted vault:", vault.Name)
+	return nil
+}
+
+// SwitchVault makes id the active vault; every session read and write,
+// and the avatar files served to the frontend, then use its files.
+func (v *VaultService) SwitchVault(id string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	vault := findVault(v.registry.Vaults, id)
+	if vault == nil {
+		return fmt.Errorf("vault not found")
+	}
+	if id == v.registry.ActiveVault {
+		return nil
+	}
+
+	registry := v.registry
+	registry.ActiveVault = id
+	if err := v.saveRegistry(registry); err != nil {
+		return err
+	}
+	v.sessionStore.open(vaultSessionPath(id))
+
+	fmt.Println("🗄️ Switched to vault:", vault.Name)
+	if v.ctx != nil {
+		runtime.EventsEmit(v.ctx, EventVaultChanged, id)
+	}
+	return nil
+}
+
+// validateName trims name and checks it's usable and not taken by a vault
+// other than exceptID. Callers must hold v.mu.
+func (v *VaultService) validateName(name string, exceptID string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("vault name can't be empty")
+	}
+	if len([]rune(name)) > maxVaultNameLength {
+		return "", fmt.Errorf("vault name can be at most %d characters", maxVaultNameLength)
+	}
+	for _, vault := range v.registry.Vaults {
+		if vault.ID != exceptID && strings.EqualFold(vault.Name, name) {
+			return "", fmt.Errorf("a vault named %q already exists", vault.Name)
+		}
+	}
+	return name, nil
+}
+
+func (v *VaultService) loadRegistry() error {
+	v.registry = vaultRegistry{ActiveVault: defaultVaultID}
+	defer func() {
+		if findVault(v.registry.Vaults, defaultVaultID) == nil {
+			v.registry.Vaults = append([]Vault{{ID: defaultVaultID, Name: "Default"}}, v.registry.Vaults...)
+		}
+	}()
+
+	data, err := os.ReadFile(v.registryPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &v.registry)
+}
+
+// saveRegistry persists registry and makes it current. Callers must hold v.mu.
+func (v *VaultService) saveRegistry(registry vaultRegistry) error {
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.registryPath), 0755); err != nil {
+		return err
+	}
+	if err := utils.WriteFileAtomic(v.registryPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save vault list: %w", err)
+	}
+	v.registry = registry
+	return nil
+}
+
+// findVault returns a pointer into vaults for the given id, or nil.
+func findVault(vaults []Vault, id string) *Vault {
+	for i := range vaults {
+		if vaults[i].ID == id {
+			return &vaults[i]
+		}
+	}
+	return nil
+}
+
+func newVaultID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// vaultDir returns the folder holding a vault's files. The default vault
+// uses the app data folder itself.
+func vaultDir(id string) string {
+	if id == defaultVaultID {
+		return utils.GetAppDataPath()
+	}
+	return filepath.Join(utils.GetAppDataPath(), "vaults", id)
+}
+
+// vaultSessionPath returns the sessions file of a vault.
+func vaultSessionPath(id string) string {
+	return filepath.Join(vaultDir(id), "login_sessions.json")
+}
+
+// vaultSessionFiles returns the sessions file path of every vault folder on
+// disk. The file itself may be missing, e.g. once the vault was migrated to
+// the key-value store; see rewriteVaultTokens.
+func vaultSessionFiles() []string {
+	paths := []string{vaultSessionPath(defaultVaultID)}
+	entries, _ := os.ReadDir(filepath.Join(utils.GetAppDataPath(), "vaults"))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			paths = append(paths, vaultSessionPath(entry.Name()))
+		}
+	}
+	return paths
+}
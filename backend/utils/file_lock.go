@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrFileLocked is returned by TryLockFile when another process holds the lock.
+var ErrFileLocked = errors.New("file is locked by another process")
+
+// FileLock is an exclusive lock on a file, held across processes until
+// Unlock is called or the process exits.
+type FileLock struct {
+	file *os.File
+}
+
+// TryLockFile takes an exclusive lock on path without blocking, creating the
+// file if needed. It returns ErrFileLocked if the lock is already held.
+func TryLockFile(path string) (*FileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tryLockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &FileLock{file: f}, nil
+}
+
+// Unlock releases the lock and closes the underlying file.
+func (l *FileLock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	unlockFile(l.file)
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
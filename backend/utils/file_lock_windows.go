@@ -0,0 +1,26 @@
+//go:build windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func tryLockFile(f *os.File) error {
+	// Lock the first byte only; that's enough to exclude other lockers
+	ol := new(windows.Overlapped)
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrFileLocked
+	}
+	return err
+}
+
+func unlockFile(f *os.File) {
+	ol := new(windows.Overlapped)
+	windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
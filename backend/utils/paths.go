@@ -39,3 +39,13 @@ func GetEpicDataPath() string {
 	userDir, _ := os.UserHomeDir()
 	return filepath.Join(userDir, "AppData", "Local", "EpicGamesLauncher", "Saved", "Data")
 }
+
+// Returns the folder where the Epic Games Launcher keeps one .item manifest
+// per installed game.
+func GetEpicManifestsPath() string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		programData = `C:\ProgramData`
+	}
+	return filepath.Join(programData, "Epic", "EpicGamesLauncher", "Data", "Manifests")
+}
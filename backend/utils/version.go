@@ -0,0 +1,8 @@
+package utils
+
+// AppVersion is the running app's version, recorded in files we write so
+// they can be traced back to the release that produced them.
+// Overridden at build time with:
+//
+//	-ldflags "-X epic-games-account-switcher/backend/utils.AppVersion=v1.2.3"
+var AppVersion = "dev"
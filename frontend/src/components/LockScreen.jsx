@@ -0,0 +1,101 @@
+import { useEffect, useRef, useState } from 'react';
+import { createPortal } from 'react-dom';
+import { HiOutlineLockClosed } from 'react-icons/hi';
+import { GetLockStatus, ReportActivity, Unlock } from '../../wailsjs/go/services/LockService';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import styles from './modals/ModalShared.module.css';
+import inputStyles from './modals/EditAliasModal.module.css';
+
+// User input only counts towards the idle auto-lock this often
+const ACTIVITY_REPORT_INTERVAL_MS = 30 * 1000;
+const ACTIVITY_EVENTS = ['mousemove', 'mousedown', 'keydown', 'wheel', 'touchstart'];
+
+// Covers the app while the master-password lock is engaged, and reports user
+// input to the backend so the idle auto-lock only fires when nobody is using it.
+export default function LockScreen() {
+  const [locked, setLocked] = useState(false);
+  const [password, setPassword] = useState('');
+  const [error, setError] = useState('');
+  const [isUnlocking, setIsUnlocking] = useState(false);
+  const lastReportRef = useRef(0);
+
+  useEffect(() => {
+    GetLockStatus()
+      .then(status => setLocked(status.locked))
+      .catch(err => console.error("❌ Failed to read lock status:", err));
+
+    const stopLocked = EventsOn('app:locked', () => setLocked(true));
+    const stopUnlocked = EventsOn('app:unlocked', () => setLocked(false));
+    return () => {
+      stopLocked();
+      stopUnlocked();
+    };
+  }, []);
+
+  useEffect(() => {
+    if (locked) return;
+
+    const handleActivity = () => {
+      const now = Date.now();
+      if (now - lastReportRef.current < ACTIVITY_REPORT_INTERVAL_MS) return;
+      lastReportRef.current = now;
+      ReportActivity().catch(() => {});
+    };
+
+    ACTIVITY_EVENTS.forEach(name => window.addEventListener(name, handleActivity, { passive: true }));
+    return () => ACTIVITY_EVENTS.forEach(name => window.removeEventListener(name, handleActivity));
+  }, [locked]);
+
+  async function handleUnlock(e) {
+    e.preventDefault();
+    if (!password || isUnlocking) return;
+
+    setIsUnlocking(true);
+    try {
+      await Unlock(password);
+      setPassword('');
+      setError('');
+      setLocked(false);
+    } catch (err) {
+      setError(String(err));
+    } finally {
+      setIsUnlocking(false);
+    }
+  }
+
+  if (!locked) return null;
+
+  return createPortal(
+    <div className={styles.modalOverlay}>
+      <form className={styles.modal} onSubmit={handleUnlock}>
+        <h3><HiOutlineLockClosed className={styles.modalTitleIcon} />Epic Switcher is locked</h3>
+
+        <div className={inputStyles.bodyContent}>
+          <p className={inputStyles.infoText}>
+            Enter the master password to see and switch accounts.
+          </p>
+          <div className={inputStyles.inputContainer}>
+            <input
+              type="password"
+              className={inputStyles.input}
+              value={password}
+              placeholder="Master password"
+              onChange={(e) => setPassword(e.target.value)}
+              autoFocus
+            />
+          </div>
+          {error && <p className={inputStyles.infoText}>{error}</p>}
+        </div>
+
+        <div className={styles.modalButtons}>
+          <div className={styles.modalButtonRow}>
+            <button type="submit" className={styles.primaryButton} disabled={!password || isUnlocking}>
+              {isUnlocking ? 'Unlocking…' : 'Unlock'}
+            </button>
+          </div>
+        </div>
+      </form>
+    </div>,
+    document.body
+  );
+}
@@ -1,12 +1,53 @@
 import { createContext, useState, useEffect } from 'react';
 import { SyncUsernames } from '../../wailsjs/go/services/LogReaderService';
-import { LoadSessions, UpdateAlias } from '../../wailsjs/go/services/SessionStore';
+import { LoadSessions, RepairSessions, UpdateAlias } from '../../wailsjs/go/services/SessionStore';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
+import toast from 'react-hot-toast';
 
 export const SessionContext = createContext();
 
+// Prefix of the backend's SessionFileCorruptError message
+const SESSION_FILE_CORRUPT = 'SESSION_FILE_CORRUPT';
+
 export function SessionProvider({ children }) {
   const [sessions, setSessions] = useState([]);
   const [isLoading, setIsLoading] = useState(true);
+  const [sessionFileCorrupt, setSessionFileCorrupt] = useState(false);
+
+  // Offers a repair when the sessions file can't be read; returns whether
+  // err was that error
+  function handleCorruptSessions(err) {
+    if (!String(err).includes(SESSION_FILE_CORRUPT)) return false;
+    setSessionFileCorrupt(true);
+    toast.error(
+      <span>
+        Your saved accounts file is damaged.
+        <button type="button" onClick={() => repairSessions()} style={{ marginLeft: 8 }}>
+          Repair
+        </button>
+      </span>,
+      { id: "sessions-corrupt", duration: Infinity }
+    );
+    return true;
+  }
+
+  async function repairSessions() {
+    toast.loading("Repairing saved accounts…", { id: "sessions-corrupt" });
+    try {
+      const report = await RepairSessions();
+      const loaded = await LoadSessions();
+      setSessions(loaded || []);
+      setSessionFileCorrupt(false);
+      toast.success(
+        `Recovered ${report.recovered} account(s)` +
+          (report.skipped ? `, ${report.skipped} couldn't be read` : '') + '.',
+        { id: "sessions-corrupt" }
+      );
+    } catch (err) {
+      console.error("❌ Failed to repair sessions:", err);
+      toast.error("Failed to repair saved accounts.", { id: "sessions-corrupt" });
+    }
+  }
 
   // Initial load
   useEffect(() => {
@@ -22,6 +63,7 @@ export function SessionProvider({ children }) {
         console.log(`✅ Loaded ${loaded?.length} sessions.`);
         console.log(`Sessions:`, loaded);
       } catch (err) {
+        handleCorruptSessions(err);
         console.error("❌ Failed to init sessions:", err);
       } finally {
         setIsLoading(false);
@@ -44,6 +86,7 @@ export function SessionProvider({ children }) {
         // console.log("🔄 JSON store changed; executed LoadSessions on window focus.");
         // }
       } catch (err) {
+        handleCorruptSessions(err);
         console.error("❌ Failed to sync usernames on focus:", err);
       }
     }
@@ -52,6 +95,48 @@ export function SessionProvider({ children }) {
     return () => window.removeEventListener('focus', handleFocus);
   }, []);
 
+  // Vault switch listener
+  useEffect(() => {
+    return EventsOn('vault:changed', async () => {
+      try {
+        const loaded = await LoadSessions();
+        setSessions(loaded || []);
+        console.log("🗄️ Vault changed; reloaded sessions.");
+      } catch (err) {
+        handleCorruptSessions(err);
+        console.error("❌ Failed to reload sessions after vault change:", err);
+      }
+    });
+  }, []);
+
+  // Unlock listener: sessions can't be read while the app is locked
+  useEffect(() => {
+    return EventsOn('app:unlocked', async () => {
+      try {
+        const loaded = await LoadSessions();
+        setSessions(loaded || []);
+      } catch (err) {
+        handleCorruptSessions(err);
+        console.error("❌ Failed to reload sessions after unlocking:", err);
+      }
+    });
+  }, []);
+
+  // External change listener: the sessions file was edited outside this window
+  useEffect(() => {
+    return EventsOn('sessions:changed', ({ added = [], changed = [], removed = [] }) => {
+      setSessions(prev => {
+        const changedById = new Map(changed.map(s => [s.userId, s]));
+        const next = prev
+          .filter(s => !removed.includes(s.userId))
+          .map(s => changedById.get(s.userId) || s)
+          .concat(added);
+        return next.sort((a, b) => (b.pinned ? 1 : 0) - (a.pinned ? 1 : 0) || (a.sortOrder ?? 0) - (b.sortOrder ?? 0));
+      });
+      console.log(`🔄 Sessions changed on disk: +${added.length} ~${changed.length} -${removed.length}`);
+    });
+  }, []);
+
   async function onAliasChange(userId, newAlias) {
     try {
       await UpdateAlias(userId, newAlias);
@@ -66,7 +151,7 @@ export function SessionProvider({ children }) {
   }
 
   return (
-    <SessionContext.Provider value={{ sessions, setSessions, isLoading, onAliasChange }}>
+    <SessionContext.Provider value={{ sessions, setSessions, isLoading, onAliasChange, sessionFileCorrupt, repairSessions }}>
       {children}
     </SessionContext.Provider>
   );
@@ -10,6 +10,7 @@ import TopNav from '../components/TopNav';
 import { STORAGE_KEYS } from '../constants/storageKeys';
 import { SupportCoffee } from '../components/SupportCoffee';
 import HintMessage from '../components/HintMessage';
+import LockScreen from '../components/LockScreen';
 
 function MainLayout({ children }) {
   const { pathname } = useLocation();
@@ -93,6 +94,9 @@ function MainLayout({ children }) {
         </div>
       )}
 
+      {/* Master-password lock; renders nothing while unlocked */}
+      <LockScreen />
+
       {/* Global toast container */}
       <Toaster
         position="bottom-center"
@@ -9,7 +9,8 @@ import { LoadSessions } from '../../wailsjs/go/services/SessionStore';
 import { HiOutlineCheckCircle, HiOutlineInformationCircle, HiViewGrid, HiViewList, HiPlus, HiPencil } from 'react-icons/hi';
 import styles from './Accounts.module.css';
 import { ViewModeContext } from '../context/ViewModeContext';
-import { SwitchAccount } from "../../wailsjs/go/services/SwitchService";
+import { SwitchAccount, CancelSwitch } from "../../wailsjs/go/services/SwitchService";
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import { STORAGE_KEYS } from "../constants/storageKeys";
 import CustomizeAvatarModal from '../components/modals/CustomizeAvatarModal';
 import AddAccountModal from '../components/modals/AddAccountModal';
@@ -18,6 +19,25 @@ import { useAvatarCache } from '../context/AvatarCacheContext';
 import { getBorderThickness } from '../components/modals/CustomizeAvatarModal/avatarUtils';
 import SuccessSprout from '../components/SuccessSprout';
 
+// Progress shown while switching, per backend switch stage
+const SWITCH_STAGE_LABELS = {
+  'check-game': 'Checking the game is installed…',
+  'preflight': 'Checking for running games and downloads…',
+  'pre-hook': 'Running the pre-switch hook…',
+  'close-launcher': 'Closing Epic Games Launcher…',
+  'kill-helpers': 'Cleaning up launcher processes…',
+  'write-session': 'Writing the new session…',
+  'relaunch': 'Relaunching Epic Games Launcher…',
+  'verify': 'Waiting for the launcher to sign in…',
+  'wait-launcher': 'Waiting for the launcher to be ready…',
+  'launch-game': 'Launching the game…',
+  'post-hook': 'Running the post-switch hook…',
+  'rollback': 'Switch failed, rolling back…',
+};
+
+// Stages that can still be cancelled; the session file isn't written yet
+const CANCELLABLE_SWITCH_STAGES = ['preflight', 'pre-hook', 'close-launcher', 'kill-helpers'];
+
 export default function Accounts() {
   const location = useLocation();
   const { sessions, setSessions, isLoading } = useContext(SessionContext);
@@ -85,10 +105,36 @@ export default function Accounts() {
 
     setIsSwitchingAccount(true);
     setSwitchingToId(session.userId);
+    const stopProgress = EventsOn('switch:stage', ({ stage, status }) => {
+      if (status !== 'started' || !SWITCH_STAGE_LABELS[stage]) return;
+      const cancellable = CANCELLABLE_SWITCH_STAGES.includes(stage);
+      toast.loading(
+        <span>
+          {SWITCH_STAGE_LABELS[stage]}
+          {cancellable && (
+            <button type="button" onClick={() => CancelSwitch().catch(() => {})} style={{ marginLeft: 8 }}>
+              Cancel
+            </button>
+          )}
+        </span>,
+        { id: "switch-progress" }
+      );
+    });
     try {
       const stored = localStorage.getItem(STORAGE_KEYS.LAUNCHER_MINIMIZED_ON_SWITCH);
       const launchMinimized = stored !== null ? stored === 'true' : true;
-      await SwitchAccount(session, launchMinimized);
+      try {
+        await SwitchAccount(session, launchMinimized, false);
+      } catch (err) {
+        // A running game or download blocks the switch unless the user insists
+        if (!String(err).startsWith("switch blocked")) throw err;
+        // Download detection reads the launcher's log and is only best-effort
+        if (!window.confirm(`${err}.\n\nClosing the launcher will interrupt them. Downloads are detected on a best-effort basis, so check the launcher's Downloads page too. Switch anyway?`)) {
+          toast("Switch cancelled.", { id: "switch-account-error" });
+          return;
+        }
+        await SwitchAccount(session, launchMinimized, true);
+      }
       // toast.success(`Switched to account: ${session.alias || session.username || session.userId}`, { id: "switch-account" });
       setActiveLoginSession(session);
       setLastSwitchedId(session.userId);
@@ -99,8 +145,14 @@ export default function Accounts() {
       }, 2500);
     } catch (err) {
       console.error(err);
-      toast.error("Failed to switch account.", { id: "switch-account-error" });
+      if (String(err).startsWith("switch cancelled")) {
+        toast("Switch cancelled.", { id: "switch-account-error" });
+      } else {
+        toast.error("Failed to switch account.", { id: "switch-account-error" });
+      }
     } finally {
+      stopProgress();
+      toast.dismiss("switch-progress");
       setSwitchingToId(null);
       setIsSwitchingAccount(false);
     }
@@ -1,14 +1,87 @@
 export namespace models {
 	
+	export class AppSettings {
+	    trashRetentionDays: number;
+	    verifySwitch: boolean;
+	    verifySwitchTimeoutSeconds: number;
+	    preSwitchHook: string;
+	    postSwitchHook: string;
+	    hookTimeoutSeconds: number;
+	    abortOnPreHookFailure: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AppSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trashRetentionDays = source["trashRetentionDays"];
+	        this.verifySwitch = source["verifySwitch"];
+	        this.verifySwitchTimeoutSeconds = source["verifySwitchTimeoutSeconds"];
+	        this.preSwitchHook = source["preSwitchHook"];
+	        this.postSwitchHook = source["postSwitchHook"];
+	        this.hookTimeoutSeconds = source["hookTimeoutSeconds"];
+	        this.abortOnPreHookFailure = source["abortOnPreHookFailure"];
+	    }
+	}
+	export class DeletedSession {
+	    username: string;
+	    userId: string;
+	    alias: string;
+	    loginToken: string;
+	    tokenFingerprint?: string;
+	    created_at: string;
+	    updated_at: string;
+	    avatarImage: string;
+	    avatarColor: string;
+	    tags?: string[];
+	    sortOrder: number;
+	    pinned?: boolean;
+	    lastUsedAt?: string;
+	    switchCount?: number;
+	    needsRelogin?: boolean;
+	    deletedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletedSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.username = source["username"];
+	        this.userId = source["userId"];
+	        this.alias = source["alias"];
+	        this.loginToken = source["loginToken"];
+	        this.tokenFingerprint = source["tokenFingerprint"];
+	        this.created_at = source["created_at"];
+	        this.updated_at = source["updated_at"];
+	        this.avatarImage = source["avatarImage"];
+	        this.avatarColor = source["avatarColor"];
+	        this.tags = source["tags"];
+	        this.sortOrder = source["sortOrder"];
+	        this.pinned = source["pinned"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	        this.switchCount = source["switchCount"];
+	        this.needsRelogin = source["needsRelogin"];
+	        this.deletedAt = source["deletedAt"];
+	    }
+	}
 	export class LoginSession {
 	    username: string;
 	    userId: string;
 	    alias: string;
 	    loginToken: string;
+	    tokenFingerprint?: string;
 	    created_at: string;
 	    updated_at: string;
 	    avatarImage: string;
 	    avatarColor: string;
+	    tags?: string[];
+	    sortOrder: number;
+	    pinned?: boolean;
+	    lastUsedAt?: string;
+	    switchCount?: number;
+	    needsRelogin?: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new LoginSession(source);
@@ -20,10 +93,17 @@ export namespace models {
 	        this.userId = source["userId"];
 	        this.alias = source["alias"];
 	        this.loginToken = source["loginToken"];
+	        this.tokenFingerprint = source["tokenFingerprint"];
 	        this.created_at = source["created_at"];
 	        this.updated_at = source["updated_at"];
 	        this.avatarImage = source["avatarImage"];
 	        this.avatarColor = source["avatarColor"];
+	        this.tags = source["tags"];
+	        this.sortOrder = source["sortOrder"];
+	        this.pinned = source["pinned"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	        this.switchCount = source["switchCount"];
+	        this.needsRelogin = source["needsRelogin"];
 	    }
 	}
 
@@ -31,6 +111,146 @@ export namespace models {
 
 export namespace services {
 	
+	export class AuditEntry {
+	    time: string;
+	    operation: string;
+	    source: string;
+	    userId: string;
+	    before?: Record<string, any>;
+	    after?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.operation = source["operation"];
+	        this.source = source["source"];
+	        this.userId = source["userId"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	}
+	export class BatchReport {
+	    applied: boolean;
+	    results: BatchResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.applied = source["applied"];
+	        this.results = this.convertValues(source["results"], BatchResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BatchResult {
+	    userId: string;
+	    changed: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.userId = source["userId"];
+	        this.changed = source["changed"];
+	        this.error = source["error"];
+	    }
+	}
+	export class BundleAvatar {
+	    filename: string;
+	    sha256: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleAvatar(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filename = source["filename"];
+	        this.sha256 = source["sha256"];
+	    }
+	}
+	export class BundleManifest {
+	    formatVersion: number;
+	    appVersion: string;
+	    createdAt: string;
+	    sessionCount: number;
+	    avatars: BundleAvatar[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BundleManifest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.formatVersion = source["formatVersion"];
+	        this.appVersion = source["appVersion"];
+	        this.createdAt = source["createdAt"];
+	        this.sessionCount = source["sessionCount"];
+	        this.avatars = this.convertValues(source["avatars"], BundleAvatar);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateGroup {
+	    userIds: string[];
+	    reasons: string[];
+	    confidence: string;
+	    suggestedSurvivor: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.userIds = source["userIds"];
+	        this.reasons = source["reasons"];
+	        this.confidence = source["confidence"];
+	        this.suggestedSurvivor = source["suggestedSurvivor"];
+	    }
+	}
 	export class GitHubRelease {
 	    tag_name: string;
 	    html_url: string;
@@ -45,6 +265,30 @@ export namespace services {
 	        this.html_url = source["html_url"];
 	    }
 	}
+	export class HookResult {
+	    hook: string;
+	    command: string;
+	    exitCode: number;
+	    output?: string;
+	    durationMs: number;
+	    timedOut?: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HookResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hook = source["hook"];
+	        this.command = source["command"];
+	        this.exitCode = source["exitCode"];
+	        this.output = source["output"];
+	        this.durationMs = source["durationMs"];
+	        this.timedOut = source["timedOut"];
+	        this.error = source["error"];
+	    }
+	}
 	export class ImageMetadata {
 	    filename: string;
 	    size: number;
@@ -69,6 +313,274 @@ export namespace services {
 	        this.contentType = source["contentType"];
 	    }
 	}
+	export class ImportConflict {
+	    userId: string;
+	    existingName: string;
+	    incomingName: string;
+	    existingUpdatedAt: string;
+	    incomingUpdatedAt: string;
+	    incomingIsNewer: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.userId = source["userId"];
+	        this.existingName = source["existingName"];
+	        this.incomingName = source["incomingName"];
+	        this.existingUpdatedAt = source["existingUpdatedAt"];
+	        this.incomingUpdatedAt = source["incomingUpdatedAt"];
+	        this.incomingIsNewer = source["incomingIsNewer"];
+	    }
+	}
+	export class ImportPreview {
+	    manifest: BundleManifest;
+	    new: string[];
+	    conflicts: ImportConflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.manifest = this.convertValues(source["manifest"], BundleManifest);
+	        this.new = source["new"];
+	        this.conflicts = this.convertValues(source["conflicts"], ImportConflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportResult {
+	    added: string[];
+	    overwritten: string[];
+	    skipped: string[];
+	    avatars: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.overwritten = source["overwritten"];
+	        this.skipped = source["skipped"];
+	        this.avatars = source["avatars"];
+	    }
+	}
+	export class InstalledGame {
+	    appName: string;
+	    displayName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledGame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.appName = source["appName"];
+	        this.displayName = source["displayName"];
+	    }
+	}
+	export class LockStatus {
+	    enabled: boolean;
+	    locked: boolean;
+	    idleMinutes: number;
+	    retryAfterSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LockStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.locked = source["locked"];
+	        this.idleMinutes = source["idleMinutes"];
+	        this.retryAfterSeconds = source["retryAfterSeconds"];
+	    }
+	}
+	export class RepairReport {
+	    recovered: number;
+	    trashRecovered: number;
+	    skipped: number;
+	    quarantinePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepairReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.recovered = source["recovered"];
+	        this.trashRecovered = source["trashRecovered"];
+	        this.skipped = source["skipped"];
+	        this.quarantinePath = source["quarantinePath"];
+	    }
+	}
+	export class SwitchBlocker {
+	    kind: string;
+	    appName?: string;
+	    displayName: string;
+	    process?: string;
+	    pid?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SwitchBlocker(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.appName = source["appName"];
+	        this.displayName = source["displayName"];
+	        this.process = source["process"];
+	        this.pid = source["pid"];
+	    }
+	}
+	export class SwitchReport {
+	    userId: string;
+	    steps: string[];
+	    durationsMs: Record<string, number>;
+	    totalMs: number;
+	    failedStep?: string;
+	    cancelled?: boolean;
+	    error?: string;
+	    rolledBack?: string[];
+	    rollbackErrors?: string[];
+	    blockers?: SwitchBlocker[];
+	    verification?: SwitchVerification;
+	    hooks?: HookResult[];
+	    appName?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SwitchReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.userId = source["userId"];
+	        this.steps = source["steps"];
+	        this.durationsMs = source["durationsMs"];
+	        this.totalMs = source["totalMs"];
+	        this.failedStep = source["failedStep"];
+	        this.cancelled = source["cancelled"];
+	        this.error = source["error"];
+	        this.rolledBack = source["rolledBack"];
+	        this.rollbackErrors = source["rollbackErrors"];
+	        this.blockers = this.convertValues(source["blockers"], SwitchBlocker);
+	        this.verification = this.convertValues(source["verification"], SwitchVerification);
+	        this.hooks = this.convertValues(source["hooks"], HookResult);
+	        this.appName = source["appName"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SwitchVerification {
+	    result: string;
+	    activeUserId?: string;
+	    reason?: string;
+	    elapsedMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SwitchVerification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.result = source["result"];
+	        this.activeUserId = source["activeUserId"];
+	        this.reason = source["reason"];
+	        this.elapsedMs = source["elapsedMs"];
+	    }
+	}
+	export class TagCount {
+	    tag: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TagCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tag = source["tag"];
+	        this.count = source["count"];
+	    }
+	}
+	export class TokenImportRequest {
+	    iniPath: string;
+	    token: string;
+	    userId: string;
+	    username: string;
+	    alias: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TokenImportRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.iniPath = source["iniPath"];
+	        this.token = source["token"];
+	        this.userId = source["userId"];
+	        this.username = source["username"];
+	        this.alias = source["alias"];
+	    }
+	}
+	export class Vault {
+	    id: string;
+	    name: string;
+	    createdAt: string;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Vault(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.createdAt = source["createdAt"];
+	        this.active = source["active"];
+	    }
+	}
 
 }
 
@@ -2,6 +2,12 @@
 // This file is automatically generated. DO NOT EDIT
 import {services} from '../models';
 
+export function BatchRemoveAvatar(arg1:Array<string>):Promise<services.BatchReport>;
+
+export function BatchSetAvatar(arg1:Array<string>,arg2:string):Promise<services.BatchReport>;
+
+export function BatchSetAvatarColor(arg1:Array<string>,arg2:string):Promise<services.BatchReport>;
+
 export function DeleteAvatarFile(arg1:string):Promise<void>;
 
 export function GetAvailableAvatars():Promise<Array<string>>;
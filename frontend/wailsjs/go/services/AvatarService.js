@@ -2,6 +2,18 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BatchRemoveAvatar(arg1) {
+  return window['go']['services']['AvatarService']['BatchRemoveAvatar'](arg1);
+}
+
+export function BatchSetAvatar(arg1, arg2) {
+  return window['go']['services']['AvatarService']['BatchSetAvatar'](arg1, arg2);
+}
+
+export function BatchSetAvatarColor(arg1, arg2) {
+  return window['go']['services']['AvatarService']['BatchSetAvatarColor'](arg1, arg2);
+}
+
 export function DeleteAvatarFile(arg1) {
   return window['go']['services']['AvatarService']['DeleteAvatarFile'](arg1);
 }
@@ -1,15 +1,64 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {services} from '../models';
+
+export function AddTag(arg1:string,arg2:string):Promise<void>;
+
+export function BatchAddTag(arg1:Array<string>,arg2:string):Promise<services.BatchReport>;
+
+export function BatchDelete(arg1:Array<string>):Promise<services.BatchReport>;
+
+export function BatchRemoveTag(arg1:Array<string>,arg2:string):Promise<services.BatchReport>;
+
+export function BatchResetAliases(arg1:Array<string>):Promise<services.BatchReport>;
 
 export function DeleteSession(arg1:string):Promise<void>;
 
+export function EmptyTrash():Promise<void>;
+
+export function FindDuplicateSessions():Promise<Array<services.DuplicateGroup>>;
+
+export function GetAccountHistory(arg1:string):Promise<Array<services.AuditEntry>>;
+
 export function GetAvatarDir():Promise<string>;
 
+export function GetDeletedSessions():Promise<Array<models.DeletedSession>>;
+
+export function GetSessionsByFrequentUse():Promise<Array<models.LoginSession>>;
+
+export function GetSessionsByRecentUse():Promise<Array<models.LoginSession>>;
+
+export function GetStorageBackend():Promise<string>;
+
+export function IsReadOnly():Promise<boolean>;
+
+export function ListTags():Promise<Array<services.TagCount>>;
+
 export function LoadSessions():Promise<Array<models.LoginSession>>;
 
+export function MergeSessions(arg1:string,arg2:Array<string>):Promise<models.LoginSession>;
+
+export function MigrateStorage(arg1:string):Promise<void>;
+
+export function PurgeDeletedSession(arg1:string):Promise<void>;
+
+export function PurgeExpiredTrash():Promise<number>;
+
+export function QuerySessionsByTags(arg1:Array<string>,arg2:boolean):Promise<Array<models.LoginSession>>;
+
+export function RemoveTag(arg1:string,arg2:string):Promise<void>;
+
+export function ReorderSessions(arg1:Array<string>):Promise<void>;
+
+export function RepairSessions():Promise<services.RepairReport>;
+
+export function RestoreSession(arg1:string):Promise<void>;
+
 export function SaveSessions(arg1:Array<models.LoginSession>):Promise<void>;
 
+export function SetPinned(arg1:string,arg2:boolean):Promise<void>;
+
 export function UpdateAlias(arg1:string,arg2:string):Promise<void>;
 
 export function UpdateAvatarColor(arg1:string,arg2:string):Promise<void>;
@@ -2,22 +2,118 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddTag(arg1, arg2) {
+  return window['go']['services']['SessionStore']['AddTag'](arg1, arg2);
+}
+
+export function BatchAddTag(arg1, arg2) {
+  return window['go']['services']['SessionStore']['BatchAddTag'](arg1, arg2);
+}
+
+export function BatchDelete(arg1) {
+  return window['go']['services']['SessionStore']['BatchDelete'](arg1);
+}
+
+export function BatchRemoveTag(arg1, arg2) {
+  return window['go']['services']['SessionStore']['BatchRemoveTag'](arg1, arg2);
+}
+
+export function BatchResetAliases(arg1) {
+  return window['go']['services']['SessionStore']['BatchResetAliases'](arg1);
+}
+
 export function DeleteSession(arg1) {
   return window['go']['services']['SessionStore']['DeleteSession'](arg1);
 }
 
+export function EmptyTrash() {
+  return window['go']['services']['SessionStore']['EmptyTrash']();
+}
+
+export function FindDuplicateSessions() {
+  return window['go']['services']['SessionStore']['FindDuplicateSessions']();
+}
+
+export function GetAccountHistory(arg1) {
+  return window['go']['services']['SessionStore']['GetAccountHistory'](arg1);
+}
+
 export function GetAvatarDir() {
   return window['go']['services']['SessionStore']['GetAvatarDir']();
 }
 
+export function GetDeletedSessions() {
+  return window['go']['services']['SessionStore']['GetDeletedSessions']();
+}
+
+export function GetSessionsByFrequentUse() {
+  return window['go']['services']['SessionStore']['GetSessionsByFrequentUse']();
+}
+
+export function GetSessionsByRecentUse() {
+  return window['go']['services']['SessionStore']['GetSessionsByRecentUse']();
+}
+
+export function GetStorageBackend() {
+  return window['go']['services']['SessionStore']['GetStorageBackend']();
+}
+
+export function IsReadOnly() {
+  return window['go']['services']['SessionStore']['IsReadOnly']();
+}
+
+export function ListTags() {
+  return window['go']['services']['SessionStore']['ListTags']();
+}
+
 export function LoadSessions() {
   return window['go']['services']['SessionStore']['LoadSessions']();
 }
 
+export function MergeSessions(arg1, arg2) {
+  return window['go']['services']['SessionStore']['MergeSessions'](arg1, arg2);
+}
+
+export function MigrateStorage(arg1) {
+  return window['go']['services']['SessionStore']['MigrateStorage'](arg1);
+}
+
+export function PurgeDeletedSession(arg1) {
+  return window['go']['services']['SessionStore']['PurgeDeletedSession'](arg1);
+}
+
+export function PurgeExpiredTrash() {
+  return window['go']['services']['SessionStore']['PurgeExpiredTrash']();
+}
+
+export function QuerySessionsByTags(arg1, arg2) {
+  return window['go']['services']['SessionStore']['QuerySessionsByTags'](arg1, arg2);
+}
+
+export function RemoveTag(arg1, arg2) {
+  return window['go']['services']['SessionStore']['RemoveTag'](arg1, arg2);
+}
+
+export function ReorderSessions(arg1) {
+  return window['go']['services']['SessionStore']['ReorderSessions'](arg1);
+}
+
+export function RepairSessions() {
+  return window['go']['services']['SessionStore']['RepairSessions']();
+}
+
+export function RestoreSession(arg1) {
+  return window['go']['services']['SessionStore']['RestoreSession'](arg1);
+}
+
 export function SaveSessions(arg1) {
   return window['go']['services']['SessionStore']['SaveSessions'](arg1);
 }
 
+export function SetPinned(arg1, arg2) {
+  return window['go']['services']['SessionStore']['SetPinned'](arg1, arg2);
+}
+
 export function UpdateAlias(arg1, arg2) {
   return window['go']['services']['SessionStore']['UpdateAlias'](arg1, arg2);
 }
@@ -1,5 +1,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {services} from '../models';
 
-export function SwitchAccount(arg1:models.LoginSession,arg2:boolean):Promise<void>;
+export function CancelSwitch():Promise<void>;
+
+export function CheckSwitchBlockers():Promise<Array<services.SwitchBlocker>>;
+
+export function ListInstalledGames():Promise<Array<services.InstalledGame>>;
+
+export function SwitchAccount(arg1:models.LoginSession,arg2:boolean,arg3:boolean):Promise<services.SwitchReport>;
+
+export function SwitchAndLaunch(arg1:string,arg2:string):Promise<services.SwitchReport>;
@@ -2,6 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function SwitchAccount(arg1, arg2) {
-  return window['go']['services']['SwitchService']['SwitchAccount'](arg1, arg2);
+export function CancelSwitch() {
+  return window['go']['services']['SwitchService']['CancelSwitch']();
+}
+
+export function CheckSwitchBlockers() {
+  return window['go']['services']['SwitchService']['CheckSwitchBlockers']();
+}
+
+export function ListInstalledGames() {
+  return window['go']['services']['SwitchService']['ListInstalledGames']();
+}
+
+export function SwitchAccount(arg1, arg2, arg3) {
+  return window['go']['services']['SwitchService']['SwitchAccount'](arg1, arg2, arg3);
+}
+
+export function SwitchAndLaunch(arg1, arg2) {
+  return window['go']['services']['SwitchService']['SwitchAndLaunch'](arg1, arg2);
 }
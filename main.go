@@ -3,6 +3,10 @@ package main
 import (
 	"context"
 	"embed"
+	"flag"
+	"io"
+	"os"
+	"strings"
 
 	"epic-games-account-switcher/backend"
 	"epic-games-account-switcher/backend/middleware"
@@ -18,16 +22,28 @@ var assets embed.FS
 
 func main() {
 	app := backend.NewApp()
-	authService := services.NewAuthService()
-	sessionStore := services.NewSessionStore()
-	logReader := services.NewLogReaderService()
-	switchService := services.NewSwitchService()
+	settingsService := services.NewSettingsService()
+	sessionStore := services.NewSessionStore(settingsService)
+	vaultService := services.NewVaultService(sessionStore)
+
+	// One-shot storage migration, e.g. `-migrate-storage=kv`. Runs before the
+	// app lock engages, since tokens are copied still encrypted.
+	if migrateTo := migrateStorageFlag(os.Args[1:]); migrateTo != "" {
+		if err := sessionStore.MigrateStorage(migrateTo); err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	lockService := services.NewLockService(sessionStore)
+	authService := services.NewAuthService(sessionStore)
+	logReader := services.NewLogReaderService(sessionStore)
+	switchService := services.NewSwitchService(sessionStore)
 	systemService := services.NewSystemService()
 	updateService := services.NewUpdateService()
-	avatarService := services.NewAvatarService()
-
-	// Get avatar directory once at startup
-	avatarDir := sessionStore.GetAvatarDir()
+	avatarService := services.NewAvatarService(sessionStore)
+	transferService := services.NewTransferService(sessionStore)
 
 	err := wails.Run(&options.App{
 		Title:     "Epic Switcher",
@@ -36,11 +52,21 @@ func main() {
 		Frameless: true,
 		AssetServer: &assetserver.Options{
 			Assets:     assets,
-			Middleware: middleware.AvatarHandler(avatarDir),
+			Middleware: middleware.AvatarHandler(sessionStore.GetAvatarDir),
 		},
 		OnStartup: func(ctx context.Context) {
 			app.Startup(ctx)
+			services.SetSessionStoreContext(sessionStore, ctx)
 			services.SetAvatarServiceContext(avatarService, ctx)
+			services.SetLockServiceContext(lockService, ctx)
+			services.SetTransferServiceContext(transferService, ctx)
+			services.SetVaultServiceContext(vaultService, ctx)
+			services.SetSwitchServiceContext(switchService, ctx)
+
+			// Drop recycle bin entries past their retention period
+			if _, err := sessionStore.PurgeExpiredTrash(); err != nil {
+				println("Skipped purging deleted sessions:", err.Error())
+			}
 		},
 		BackgroundColour: &options.RGBA{R: 16, G: 16, B: 16, A: 1},
 		Bind: []interface{}{
@@ -52,6 +78,10 @@ func main() {
 			systemService,
 			updateService,
 			avatarService,
+			lockService,
+			settingsService,
+			transferService,
+			vaultService,
 		},
 	})
 
@@ -59,3 +89,29 @@ func main() {
 		println("Error:", err.Error())
 	}
 }
+
+// migrateStorageFlag returns the value of -migrate-storage in args. Other
+// arguments, such as ones Windows or a shortcut pass along, are ignored
+// rather than making the app exit.
+func migrateStorageFlag(args []string) string {
+	picked := []string{}
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != "migrate-storage" {
+			continue
+		}
+		picked = append(picked, args[i])
+		if !hasValue && i+1 < len(args) {
+			i++
+			picked = append(picked, args[i])
+		}
+	}
+
+	flags := flag.NewFlagSet("epic-switcher", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	migrateTo := flags.String("migrate-storage", "", "move the active vault's sessions to another storage backend (json or kv) and exit")
+	if err := flags.Parse(picked); err != nil {
+		return ""
+	}
+	return *migrateTo
+}